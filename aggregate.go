@@ -0,0 +1,159 @@
+package japanmesh
+
+import "math"
+
+// Stats メッシュごとの集計値。Merge で別の集計値と合算できる。
+type Stats struct {
+	Count int64
+	Sum   float64
+	Min   float64
+	Max   float64
+	// 平均からの偏差の二乗和。別のプロセスで集計した値を Merge できるように公開する
+	M2 float64
+}
+
+// Add 値を追加する。
+func (s *Stats) Add(value float64) {
+	if s.Count == 0 {
+		*s = Stats{Count: 1, Sum: value, Min: value, Max: value}
+		return
+	}
+	// Welford のアルゴリズム
+	mean := s.Mean()
+	s.Count++
+	s.Sum += value
+	s.M2 += (value - mean) * (value - s.Mean())
+	s.Min = math.Min(s.Min, value)
+	s.Max = math.Max(s.Max, value)
+}
+
+// Merge 別の集計値を合算する。
+func (s *Stats) Merge(other Stats) {
+	if other.Count == 0 {
+		return
+	}
+	if s.Count == 0 {
+		*s = other
+		return
+	}
+	// Chan らの並列アルゴリズム
+	delta := other.Mean() - s.Mean()
+	count := s.Count + other.Count
+	s.M2 += other.M2 + delta*delta*float64(s.Count)*float64(other.Count)/float64(count)
+	s.Count = count
+	s.Sum += other.Sum
+	s.Min = math.Min(s.Min, other.Min)
+	s.Max = math.Max(s.Max, other.Max)
+}
+
+// Mean 平均を取得する。
+func (s Stats) Mean() float64 {
+	if s.Count == 0 {
+		return math.NaN()
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// Variance 分散(母分散)を取得する。
+func (s Stats) Variance() float64 {
+	if s.Count == 0 {
+		return math.NaN()
+	}
+	return s.M2 / float64(s.Count)
+}
+
+// SampleVariance 不偏分散を取得する。
+func (s Stats) SampleVariance() float64 {
+	if s.Count < 2 {
+		return math.NaN()
+	}
+	return s.M2 / float64(s.Count-1)
+}
+
+// Aggregator 緯度経度ごとの値を地域メッシュ単位で集計する。
+// 並行して使用する場合はワーカーごとに Aggregator を用意し、Merge で合算する。
+type Aggregator struct {
+	level Level
+	stats map[MeshCode]*Stats
+}
+
+// NewAggregator 指定したレベルで集計する Aggregator を生成する。
+func NewAggregator(level Level) (*Aggregator, error) {
+	if _, ok := getMesh(level); !ok {
+		return nil, ErrInvalidLevel
+	}
+	return &Aggregator{level: level, stats: make(map[MeshCode]*Stats)}, nil
+}
+
+// Level 集計するレベルを取得する。
+func (a *Aggregator) Level() Level {
+	return a.level
+}
+
+// Add 緯度経度の値を追加する。日本の国土にかからない緯度経度の場合は ErrInvalidArea を返す。
+func (a *Aggregator) Add(geoCode GeoCode, value float64) error {
+	code, err := ToCode(geoCode, a.level)
+	if err != nil {
+		return err
+	}
+	a.add(code, Stats{Count: 1, Sum: value, Min: value, Max: value})
+	return nil
+}
+
+// Merge 別の Aggregator の集計値を合算する。集計するレベルが異なる場合は ErrInvalidLevel を返す。
+func (a *Aggregator) Merge(other *Aggregator) error {
+	if other.level != a.level {
+		return ErrInvalidLevel
+	}
+	for code, stats := range other.stats {
+		a.add(code, *stats)
+	}
+	return nil
+}
+
+// Results 指定したレベルの地域メッシュごとの集計値を取得する。
+// 集計するレベル、またはその上位のレベルのみ指定できる。
+func (a *Aggregator) Results(level Level) (map[MeshCode]Stats, error) {
+	if !isAncestorLevel(level, a.level) {
+		return nil, ErrInvalidLevel
+	}
+	results := make(map[MeshCode]Stats, len(a.stats))
+	for code, stats := range a.stats {
+		if level != a.level {
+			// 上位のレベルのメッシュにまとめる
+			code = SplitCodeByLevel(code)[getLevelIndex(level)]
+		}
+		s := results[code]
+		s.Merge(*stats)
+		results[code] = s
+	}
+	return results, nil
+}
+
+func (a *Aggregator) add(code MeshCode, stats Stats) {
+	s, ok := a.stats[code]
+	if !ok {
+		s = &Stats{}
+		a.stats[code] = s
+	}
+	s.Merge(stats)
+}
+
+// getLevelIndex SplitCodeByLevel の結果におけるレベルの位置を取得する。
+func getLevelIndex(level Level) int {
+	switch level {
+	case Level1:
+		return 0
+	case Level2:
+		return 1
+	case Level3:
+		return 2
+	case LevelHalf:
+		return 3
+	case LevelQuarter:
+		return 4
+	case LevelOneEighth:
+		return 5
+	}
+	return -1
+}
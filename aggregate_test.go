@@ -0,0 +1,118 @@
+package japanmesh
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	values := []float64{4, 8, 15, 16, 23, 42}
+	var all, a, b Stats
+	for i, v := range values {
+		all.Add(v)
+		if i%2 == 0 {
+			a.Add(v)
+		} else {
+			b.Add(v)
+		}
+	}
+	// 別のプロセスで集計した値を JSON で受け取って合算する
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var remote Stats
+	if err := json.Unmarshal(data, &remote); err != nil {
+		t.Fatal(err)
+	}
+	serialized := a
+	serialized.Merge(remote)
+	a.Merge(b)
+
+	for name, s := range map[string]Stats{"add": all, "merge": a, "serialized merge": serialized} {
+		t.Run(name, func(t *testing.T) {
+			if s.Count != 6 || s.Sum != 108 || s.Min != 4 || s.Max != 42 {
+				t.Errorf("Stats got = %+v", s)
+			}
+			if s.Mean() != 18 {
+				t.Errorf("Stats.Mean() got = %v, want %v", s.Mean(), 18)
+			}
+			// ((4-18)^2 + (8-18)^2 + (15-18)^2 + (16-18)^2 + (23-18)^2 + (42-18)^2) / 6
+			if want := 910.0 / 6; math.Abs(s.Variance()-want) > 1e-9 {
+				t.Errorf("Stats.Variance() got = %v, want %v", s.Variance(), want)
+			}
+			if want := 910.0 / 5; math.Abs(s.SampleVariance()-want) > 1e-9 {
+				t.Errorf("Stats.SampleVariance() got = %v, want %v", s.SampleVariance(), want)
+			}
+		})
+	}
+
+	var empty Stats
+	if !math.IsNaN(empty.Mean()) || !math.IsNaN(empty.Variance()) {
+		t.Errorf("empty Stats got = %v, %v", empty.Mean(), empty.Variance())
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	// 533945471 と 533945474 に2点ずつ
+	points := []struct {
+		geoCode GeoCode
+		value   float64
+	}{
+		{geoCode: GeoCode{Latitude: 35.70078, Longitude: 139.71475}, value: 1},
+		{geoCode: GeoCode{Latitude: 35.70100, Longitude: 139.71300}, value: 3},
+		{geoCode: GeoCode{Latitude: 35.70700, Longitude: 139.72300}, value: 10},
+		{geoCode: GeoCode{Latitude: 35.70800, Longitude: 139.72400}, value: 20},
+	}
+	a, _ := NewAggregator(LevelHalf)
+	b, _ := NewAggregator(LevelHalf)
+	for i, p := range points {
+		target := a
+		if i%2 == 1 {
+			target = b
+		}
+		if err := target.Add(p.geoCode, p.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Add(GeoCode{}, 1); err != ErrInvalidArea {
+		t.Errorf("Aggregator.Add() error = %v, wantErr %v", err, ErrInvalidArea)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := a.Results(LevelHalf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Aggregator.Results() got = %v", got)
+	}
+	if s := got["533945471"]; s.Count != 2 || s.Sum != 4 || s.Min != 1 || s.Max != 3 || s.Variance() != 1 {
+		t.Errorf("Aggregator.Results() 533945471 got = %+v", s)
+	}
+	if s := got["533945474"]; s.Count != 2 || s.Sum != 30 || s.Mean() != 15 {
+		t.Errorf("Aggregator.Results() 533945474 got = %+v", s)
+	}
+
+	for _, level := range []Level{Level3, Level2, Level1} {
+		got, err := a.Results(level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		code := getCodeByLevel("533945471", level)
+		if s := got[code]; len(got) != 1 || s.Count != 4 || s.Sum != 34 || s.Min != 1 || s.Max != 20 {
+			t.Errorf("Aggregator.Results(%v) got = %+v", level, got)
+		}
+	}
+
+	if _, err := a.Results(LevelQuarter); err != ErrInvalidLevel {
+		t.Errorf("Aggregator.Results() error = %v, wantErr %v", err, ErrInvalidLevel)
+	}
+	c, _ := NewAggregator(Level3)
+	if err := a.Merge(c); err != ErrInvalidLevel {
+		t.Errorf("Aggregator.Merge() error = %v, wantErr %v", err, ErrInvalidLevel)
+	}
+}
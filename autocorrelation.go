@@ -0,0 +1,230 @@
+package japanmesh
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+var ErrInsufficientData = errors.New("insufficient data")
+
+// Contiguity 隣接するメッシュの定義
+type Contiguity int
+
+const (
+	// ContiguityQueen 辺または頂点を共有する8方向のメッシュを隣接とする。
+	ContiguityQueen Contiguity = iota
+	// ContiguityRook 辺を共有する4方向のメッシュを隣接とする。
+	ContiguityRook
+)
+
+// MoranResult 大域的モランI統計量の計算結果
+type MoranResult struct {
+	I float64
+	// 空間的自己相関がない場合の期待値 -1/(n-1)
+	Expected float64
+	// 並べ替えた値の I の分布に対する標準化得点。permutations が0の場合は NaN
+	ZScore float64
+	// 観測値と同じ向きに観測値以上に極端な I が得られる割合(片側)。permutations が0の場合は NaN
+	PValue float64
+}
+
+// LocalGResult Getis-Ord Gi* 統計量の計算結果
+type LocalGResult struct {
+	// Gi* 統計量(正規近似による標準化得点)
+	ZScore float64
+	// 自身の値を固定し、隣接するメッシュの値を他のメッシュの値から無作為に選んだ場合に、
+	// 観測値と同じ向きに観測値以上に極端な値が得られる割合(片側)。permutations が0の場合は NaN
+	PValue float64
+}
+
+// contiguityWeights 隣接関係を求めたメッシュの値
+type contiguityWeights struct {
+	codes MeshCodes
+	x     []float64
+	// neighbors 各メッシュに隣接するメッシュの添字
+	neighbors [][]int
+}
+
+// MoransI 地域メッシュごとの値の大域的モランI統計量を求める。
+//
+// values に含まれるメッシュのうち、contiguity で隣接するメッシュの重みを1、それ以外を0とする。
+// 第1次地域区画の境界をまたいで隣接を判定する。
+// permutations 回、値をメッシュに無作為に割り当て直して p 値を求め、乱数の種は seed とする。
+// values に異なるレベルのメッシュが含まれる場合は ErrInvalidLevel、
+// メッシュが3未満、隣接するメッシュがない、または値がすべて等しい場合は ErrInsufficientData を返す。
+func MoransI(values map[MeshCode]float64, contiguity Contiguity, permutations int, seed int64) (*MoranResult, error) {
+	w, err := newContiguityWeights(values, contiguity)
+	if err != nil {
+		return nil, err
+	}
+	n := len(w.x)
+	var s0 int
+	for _, ns := range w.neighbors {
+		s0 += len(ns)
+	}
+	if s0 == 0 {
+		return nil, ErrInsufficientData
+	}
+	mean := ReduceMean(nil, w.x)
+	z := make([]float64, n)
+	var m2 float64
+	for i, v := range w.x {
+		z[i] = v - mean
+		m2 += z[i] * z[i]
+	}
+	if m2 == 0 {
+		return nil, ErrInsufficientData
+	}
+	moran := func(z []float64) float64 {
+		var sum float64
+		for i, ns := range w.neighbors {
+			for _, j := range ns {
+				sum += z[i] * z[j]
+			}
+		}
+		return float64(n) / float64(s0) * sum / m2
+	}
+
+	result := &MoranResult{
+		I:        moran(z),
+		Expected: -1 / float64(n-1),
+		ZScore:   math.NaN(),
+		PValue:   math.NaN(),
+	}
+	if permutations <= 0 {
+		return result, nil
+	}
+	r := rand.New(rand.NewSource(seed))
+	shuffled := append([]float64(nil), z...)
+	sims := make([]float64, permutations)
+	for k := range sims {
+		r.Shuffle(n, func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		sims[k] = moran(shuffled)
+	}
+	result.ZScore, result.PValue = permutationTest(result.I, result.I >= result.Expected, sims)
+	return result, nil
+}
+
+// GetisOrdGiStar 地域メッシュごとの Getis-Ord Gi* 統計量を求める。
+//
+// 自身と contiguity で隣接するメッシュの重みを1、それ以外を0とする。
+// 第1次地域区画の境界をまたいで隣接を判定する。
+// permutations 回、条件付き並べ替えを行って p 値を求め、乱数の種は seed とする。
+// values に異なるレベルのメッシュが含まれる場合は ErrInvalidLevel、
+// メッシュが3未満または値がすべて等しい場合は ErrInsufficientData を返す。
+func GetisOrdGiStar(values map[MeshCode]float64, contiguity Contiguity, permutations int, seed int64) (map[MeshCode]LocalGResult, error) {
+	w, err := newContiguityWeights(values, contiguity)
+	if err != nil {
+		return nil, err
+	}
+	n := len(w.x)
+	mean := ReduceMean(nil, w.x)
+	var squares float64
+	for _, v := range w.x {
+		squares += v * v
+	}
+	s := math.Sqrt(squares/float64(n) - mean*mean)
+	if !(s > 0) {
+		return nil, ErrInsufficientData
+	}
+
+	var r *rand.Rand
+	var others []int
+	if permutations > 0 {
+		r = rand.New(rand.NewSource(seed))
+		others = make([]int, n)
+	}
+	results := make(map[MeshCode]LocalGResult, n)
+	for i, code := range w.codes {
+		// 自身を含む重みの合計と、隣接するメッシュの値の合計
+		weight := float64(len(w.neighbors[i]) + 1)
+		var lag float64
+		for _, j := range w.neighbors[i] {
+			lag += w.x[j]
+		}
+		g := (w.x[i] + lag - mean*weight) / (s * math.Sqrt((float64(n)*weight-weight*weight)/float64(n-1)))
+		result := LocalGResult{ZScore: g, PValue: math.NaN()}
+		if permutations > 0 {
+			// 自身を除いた添字から、隣接するメッシュの数だけ無作為に選ぶ
+			for j := range others {
+				others[j] = j
+			}
+			others[i], others[n-1] = others[n-1], others[i]
+			k := len(w.neighbors[i])
+			sims := make([]float64, permutations)
+			for p := range sims {
+				var sum float64
+				for j := 0; j < k; j++ {
+					l := j + r.Intn(n-1-j)
+					others[j], others[l] = others[l], others[j]
+					sum += w.x[others[j]]
+				}
+				sims[p] = sum
+			}
+			_, result.PValue = permutationTest(lag, g >= 0, sims)
+		}
+		results[code] = result
+	}
+	return results, nil
+}
+
+// permutationTest 並べ替えで得た値の分布に対する観測値の標準化得点と片側の p 値を求める。
+func permutationTest(observed float64, upper bool, sims []float64) (float64, float64) {
+	var stats Stats
+	var extreme int
+	for _, v := range sims {
+		stats.Add(v)
+		if (upper && v >= observed) || (!upper && v <= observed) {
+			extreme++
+		}
+	}
+	z := math.NaN()
+	if sd := math.Sqrt(stats.SampleVariance()); sd > 0 {
+		z = (observed - stats.Mean()) / sd
+	}
+	return z, float64(extreme+1) / float64(len(sims)+1)
+}
+
+// newContiguityWeights メッシュコードの昇順に値を並べ、隣接するメッシュを求める。
+func newContiguityWeights(values map[MeshCode]float64, contiguity Contiguity) (*contiguityWeights, error) {
+	if len(values) < 3 {
+		return nil, ErrInsufficientData
+	}
+	w := &contiguityWeights{codes: make(MeshCodes, 0, len(values))}
+	for code := range values {
+		w.codes = append(w.codes, code)
+	}
+	// 並べ替えの結果を乱数の種のみで決めるため、順序を一定にする
+	sort.Slice(w.codes, func(i, j int) bool { return w.codes[i] < w.codes[j] })
+
+	index := make(map[cell]int, len(values))
+	cells := make([]cell, len(w.codes))
+	w.x = make([]float64, len(w.codes))
+	for i, code := range w.codes {
+		c, err := toCell(code)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && c.level != cells[0].level {
+			return nil, ErrInvalidLevel
+		}
+		cells[i] = c
+		index[c] = i
+		w.x[i] = values[code]
+	}
+
+	w.neighbors = make([][]int, len(cells))
+	for i, c := range cells {
+		for _, offset := range neighborOffsets {
+			if contiguity == ContiguityRook && offset[0] != 0 && offset[1] != 0 {
+				continue
+			}
+			if j, ok := index[cell{level: c.level, y: c.y + offset[0], x: c.x + offset[1]}]; ok {
+				w.neighbors[i] = append(w.neighbors[i], j)
+			}
+		}
+	}
+	return w, nil
+}
@@ -0,0 +1,151 @@
+package japanmesh
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// gridValues 533945 の南西から size×size の基準地域メッシュに値を設定する。
+func gridValues(size int, fn func(y, x int) float64) map[MeshCode]float64 {
+	values := make(map[MeshCode]float64)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			values[MeshCode("533945"+string(rune('0'+y))+string(rune('0'+x)))] = fn(y, x)
+		}
+	}
+	return values
+}
+
+func TestMoransI(t *testing.T) {
+	checkerboard := gridValues(4, func(y, x int) float64 { return float64((y + x) % 2) })
+	clustered := gridValues(6, func(y, x int) float64 {
+		if x < 3 {
+			return 10 + float64(y)
+		}
+		return float64(y)
+	})
+	tests := []struct {
+		name       string
+		values     map[MeshCode]float64
+		contiguity Contiguity
+		wantI      float64
+		wantUpper  bool
+		// 並べ替えで有意(p < 0.01)となるか
+		significant bool
+	}{
+		// 隣接するメッシュの値がすべて逆になる
+		{name: "checkerboard rook", values: checkerboard, contiguity: ContiguityRook, wantI: -1, wantUpper: false, significant: true},
+		// 辺で隣接する48組は逆、頂点で隣接する36組は同じ値
+		{name: "checkerboard queen", values: checkerboard, contiguity: ContiguityQueen, wantI: -12.0 / 84, wantUpper: false, significant: false},
+		{name: "clustered", values: clustered, contiguity: ContiguityQueen, wantI: math.NaN(), wantUpper: true, significant: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MoransI(tt.values, tt.contiguity, 199, 1)
+			if err != nil {
+				t.Fatalf("MoransI() error = %v", err)
+			}
+			if !math.IsNaN(tt.wantI) && math.Abs(got.I-tt.wantI) > 1e-12 {
+				t.Errorf("MoransI() I = %v, want %v", got.I, tt.wantI)
+			}
+			if want := -1 / float64(len(tt.values)-1); got.Expected != want {
+				t.Errorf("MoransI() Expected = %v, want %v", got.Expected, want)
+			}
+			if (got.I > got.Expected) != tt.wantUpper || (got.PValue < 0.01) != tt.significant {
+				t.Errorf("MoransI() I = %v, PValue = %v", got.I, got.PValue)
+			}
+			again, _ := MoransI(tt.values, tt.contiguity, 199, 1)
+			if !reflect.DeepEqual(got, again) {
+				t.Errorf("MoransI() not deterministic: %v, %v", got, again)
+			}
+		})
+	}
+}
+
+func TestMoransI_NoPermutation(t *testing.T) {
+	got, err := MoransI(gridValues(3, func(y, x int) float64 { return float64(y) }), ContiguityRook, 0, 1)
+	if err != nil {
+		t.Fatalf("MoransI() error = %v", err)
+	}
+	if !math.IsNaN(got.PValue) || !math.IsNaN(got.ZScore) {
+		t.Errorf("MoransI() ZScore = %v, PValue = %v, want NaN", got.ZScore, got.PValue)
+	}
+}
+
+func TestGetisOrdGiStar(t *testing.T) {
+	// 3×3 の中央のみ9: 平均1、標準偏差√8、中央の重みの合計5 → (9-5)/(√8·√((9·5-25)/8)) = 4/√20
+	center := gridValues(3, func(y, x int) float64 {
+		if y == 1 && x == 1 {
+			return 9
+		}
+		return 0
+	})
+	got, err := GetisOrdGiStar(center, ContiguityRook, 0, 1)
+	if err != nil {
+		t.Fatalf("GetisOrdGiStar() error = %v", err)
+	}
+	if want := 4 / math.Sqrt(20); math.Abs(got["53394511"].ZScore-want) > 1e-12 {
+		t.Errorf("GetisOrdGiStar() ZScore = %v, want %v", got["53394511"].ZScore, want)
+	}
+	if !math.IsNaN(got["53394511"].PValue) {
+		t.Errorf("GetisOrdGiStar() PValue = %v, want NaN", got["53394511"].PValue)
+	}
+
+	// 第1次地域区画の境界をまたぐ高い値の集まり
+	values := gridValues(8, func(y, x int) float64 { return 1 })
+	for _, code := range []MeshCode{"53380779", "53380789", "53394580", "53394570", "53390070", "53390080"} {
+		values[code] = 1
+	}
+	hot := MeshCodes{"53390070", "53390080", "53380779", "53380789"}
+	for _, code := range hot {
+		values[code] = 50
+	}
+	results, err := GetisOrdGiStar(values, ContiguityQueen, 499, 42)
+	if err != nil {
+		t.Fatalf("GetisOrdGiStar() error = %v", err)
+	}
+	if len(results) != len(values) {
+		t.Errorf("GetisOrdGiStar() len = %v, want %v", len(results), len(values))
+	}
+	for _, code := range hot {
+		if r := results[code]; r.ZScore < 1.96 || r.PValue > 0.05 {
+			t.Errorf("GetisOrdGiStar() %v = %+v, want hotspot", code, r)
+		}
+	}
+	if r := results["53394500"]; r.ZScore > 0 {
+		t.Errorf("GetisOrdGiStar() 53394500 = %+v, want coldspot", r)
+	}
+	again, _ := GetisOrdGiStar(values, ContiguityQueen, 499, 42)
+	if !reflect.DeepEqual(results, again) {
+		t.Errorf("GetisOrdGiStar() not deterministic")
+	}
+}
+
+func TestSpatialAutocorrelation_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[MeshCode]float64
+		wantErr error
+	}{
+		{name: "too few", values: map[MeshCode]float64{"53394547": 1, "53394548": 2}, wantErr: ErrInsufficientData},
+		{name: "constant", values: gridValues(3, func(int, int) float64 { return 1 }), wantErr: ErrInsufficientData},
+		{name: "mixed level", values: map[MeshCode]float64{"53394547": 1, "53394548": 2, "533945": 3}, wantErr: ErrInvalidLevel},
+		{name: "invalid", values: map[MeshCode]float64{"53394547": 1, "53394548": 2, "53394": 3}, wantErr: ErrInvalidMeshCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MoransI(tt.values, ContiguityQueen, 0, 1); !errors.Is(err, tt.wantErr) {
+				t.Errorf("MoransI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := GetisOrdGiStar(tt.values, ContiguityQueen, 0, 1); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetisOrdGiStar() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	// 隣接するメッシュがない
+	if _, err := MoransI(map[MeshCode]float64{"53394500": 1, "53394502": 2, "53394504": 3}, ContiguityQueen, 0, 1); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("MoransI() isolated error = %v, wantErr %v", err, ErrInsufficientData)
+	}
+}
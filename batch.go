@@ -0,0 +1,204 @@
+package japanmesh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// batchChunkSize ToCodes で1つのワーカーがまとめて処理する件数
+const batchChunkSize = 4096
+
+// BatchOption 一括変換の設定
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	workers int
+}
+
+// WithWorkers 一括変換で並列に処理するワーカー数を指定する。既定値は runtime.GOMAXPROCS(0)。
+func WithWorkers(n int) BatchOption {
+	return func(c *batchConfig) {
+		c.workers = n
+	}
+}
+
+func newBatchConfig(opts []BatchOption) batchConfig {
+	c := batchConfig{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.workers < 1 {
+		c.workers = 1
+	}
+	return c
+}
+
+// ItemError 一括変換で変換できなかった要素のエラー
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+func (e ItemError) Error() string {
+	return fmt.Sprintf("index %d: %v", e.Index, e.Err)
+}
+
+func (e ItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError 一括変換で変換できなかった要素のエラーの一覧(Index の昇順)
+type BatchError []ItemError
+
+func (e BatchError) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d errors: %s", len(e), strings.Join(messages, "; "))
+}
+
+// ToCodes 複数の緯度経度から地域メッシュコードを並列に取得し、入力と同じ順序で out に格納する。
+// 変換できなかった要素は out を空文字とし、処理を中断せずに BatchError として返す。
+func ToCodes(points []GeoCode, level Level, out []MeshCode, opts ...BatchOption) error {
+	if len(out) < len(points) {
+		return io.ErrShortBuffer
+	}
+	if _, ok := getMesh(level); !ok {
+		return ErrInvalidLevel
+	}
+	config := newBatchConfig(opts)
+
+	chunks := make(chan int)
+	results := make(chan BatchError)
+	var wg sync.WaitGroup
+	for w := 0; w < config.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var errs BatchError
+			for start := range chunks {
+				end := start + batchChunkSize
+				if end > len(points) {
+					end = len(points)
+				}
+				for i := start; i < end; i++ {
+					code, err := ToCode(points[i], level)
+					if err != nil {
+						errs = append(errs, ItemError{Index: i, Err: err})
+					}
+					out[i] = code
+				}
+			}
+			results <- errs
+		}()
+	}
+	go func() {
+		for start := 0; start < len(points); start += batchChunkSize {
+			chunks <- start
+		}
+		close(chunks)
+		wg.Wait()
+		close(results)
+	}()
+
+	var errs BatchError
+	for e := range results {
+		errs = append(errs, e...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+	return errs
+}
+
+// EncodeResult EncodeChannel の変換結果
+type EncodeResult struct {
+	GeoCode GeoCode
+	Code    MeshCode
+	Err     error
+}
+
+// EncodeChannel in から受け取った緯度経度を並列に地域メッシュコードへ変換し、入力と同じ順序で返す。
+// 変換できなかった要素も Err を設定して返す。in が閉じられるか ctx が終了すると、返したチャネルを閉じる。
+func EncodeChannel(ctx context.Context, in <-chan GeoCode, level Level, opts ...BatchOption) <-chan EncodeResult {
+	config := newBatchConfig(opts)
+	_, validLevel := getMesh(level)
+	type job struct {
+		geoCode GeoCode
+		result  chan EncodeResult
+	}
+	jobs := make(chan job, config.workers)
+	// 入力順に結果を待つためのキュー
+	pending := make(chan chan EncodeResult, config.workers*2)
+	out := make(chan EncodeResult)
+
+	var wg sync.WaitGroup
+	for w := 0; w < config.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				var code MeshCode
+				err := ErrInvalidLevel
+				if validLevel {
+					code, err = ToCode(j.geoCode, level)
+				}
+				j.result <- EncodeResult{GeoCode: j.geoCode, Code: code, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(pending)
+		defer close(jobs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case geoCode, ok := <-in:
+				if !ok {
+					return
+				}
+				result := make(chan EncodeResult, 1)
+				select {
+				case jobs <- job{geoCode: geoCode, result: result}:
+				case <-ctx.Done():
+					return
+				}
+				select {
+				case pending <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	go func() {
+		defer close(out)
+		for result := range pending {
+			r := <-result
+			select {
+			case out <- r:
+			case <-ctx.Done():
+				// 残りのワーカーが終了するまで結果を読み捨てる
+				for range pending {
+				}
+				wg.Wait()
+				return
+			}
+		}
+		wg.Wait()
+	}()
+	return out
+}
@@ -0,0 +1,101 @@
+package japanmesh
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestToCodes(t *testing.T) {
+	points := make([]GeoCode, 10000)
+	for i := range points {
+		points[i] = GeoCode{Latitude: 35 + float64(i)*0.0001, Longitude: 139.71475}
+	}
+	// 範囲外の緯度経度を混ぜる
+	points[3] = GeoCode{Latitude: 0, Longitude: 0}
+	points[5000] = GeoCode{Latitude: 90, Longitude: 180}
+
+	for _, workers := range []int{1, 4} {
+		out := make([]MeshCode, len(points))
+		err := ToCodes(points, Level3, out, WithWorkers(workers))
+		var batchErr BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("ToCodes() error = %v, want BatchError", err)
+		}
+		if len(batchErr) != 2 || batchErr[0].Index != 3 || batchErr[1].Index != 5000 {
+			t.Errorf("ToCodes() error = %v", batchErr)
+		}
+		if !errors.Is(batchErr[0], ErrInvalidArea) {
+			t.Errorf("ToCodes() error = %v, wantErr %v", batchErr[0], ErrInvalidArea)
+		}
+		for i, point := range points {
+			want, _ := ToCode(point, Level3)
+			if out[i] != want {
+				t.Fatalf("ToCodes() out[%d] = %v, want %v", i, out[i], want)
+			}
+		}
+	}
+}
+
+func TestToCodes_Error(t *testing.T) {
+	points := []GeoCode{{Latitude: 35.70078, Longitude: 139.71475}}
+	if err := ToCodes(points, Level3, nil); err != io.ErrShortBuffer {
+		t.Errorf("ToCodes() error = %v, wantErr %v", err, io.ErrShortBuffer)
+	}
+	if err := ToCodes(points, "4", make([]MeshCode, 1)); err != ErrInvalidLevel {
+		t.Errorf("ToCodes() error = %v, wantErr %v", err, ErrInvalidLevel)
+	}
+	out := make([]MeshCode, 1)
+	if err := ToCodes(points, Level3, out); err != nil || out[0] != "53394547" {
+		t.Errorf("ToCodes() got = %v, %v", out, err)
+	}
+}
+
+func TestEncodeChannel(t *testing.T) {
+	points := []GeoCode{
+		{Latitude: 35.70078, Longitude: 139.71475},
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 34.70078, Longitude: 135.71475},
+		{Latitude: 43.06417, Longitude: 141.34694},
+	}
+	in := make(chan GeoCode)
+	go func() {
+		for i := 0; i < 100; i++ {
+			for _, point := range points {
+				in <- point
+			}
+		}
+		close(in)
+	}()
+
+	var got []EncodeResult
+	for result := range EncodeChannel(context.Background(), in, Level3, WithWorkers(3)) {
+		got = append(got, result)
+	}
+	if len(got) != 100*len(points) {
+		t.Fatalf("EncodeChannel() len = %d, want %d", len(got), 100*len(points))
+	}
+	for i, result := range got {
+		point := points[i%len(points)]
+		code, err := ToCode(point, Level3)
+		want := EncodeResult{GeoCode: point, Code: code, Err: err}
+		if !reflect.DeepEqual(result, want) {
+			t.Fatalf("EncodeChannel() got[%d] = %v, want %v", i, result, want)
+		}
+	}
+}
+
+func TestEncodeChannel_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan GeoCode)
+	out := EncodeChannel(ctx, in, Level3)
+	in <- GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	if result := <-out; result.Code != "53394547" {
+		t.Errorf("EncodeChannel() got = %v", result)
+	}
+	cancel()
+	for range out {
+	}
+}
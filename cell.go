@@ -0,0 +1,232 @@
+package japanmesh
+
+// cell 地域メッシュを、レベルごとの格子上の位置で表したもの。
+// Y は緯度方向(南から北)、X は経度方向(西から東)の通し番号で、
+// 第1次地域区画の境界をまたいでも連続した値になる。
+type cell struct {
+	level Level
+	y     int
+	x     int
+}
+
+// maxCodeDigit メッシュコードの最大桁数
+const maxCodeDigit = 11
+
+// getMesh レベルに対応するメッシュの定義を取得する。
+func getMesh(level Level) (Mesh, bool) {
+	switch level {
+	case Level1:
+		return level1Mesh, true
+	case Level2:
+		return level2Mesh, true
+	case Level3:
+		return level3Mesh, true
+	case LevelHalf:
+		return levelHalfMesh, true
+	case LevelQuarter:
+		return levelQuarterMesh, true
+	case LevelOneEighth:
+		return levelOneEighthMesh, true
+	case LevelFivefold:
+		return levelFivefoldMesh, true
+	case LevelTwofold:
+		return levelTwofoldMesh, true
+	}
+	return Mesh{}, false
+}
+
+// getCellCount 第1次地域区画の一辺あたりのメッシュ数を取得する。
+func getCellCount(level Level) int {
+	switch level {
+	case Level1:
+		return 1
+	case Level2:
+		return level2Mesh.Division.Y
+	case Level3:
+		return level2Mesh.Division.Y * level3Mesh.Division.Y
+	case LevelHalf:
+		return getCellCount(Level3) * levelHalfMesh.Division.Y
+	case LevelQuarter:
+		return getCellCount(LevelHalf) * levelQuarterMesh.Division.Y
+	case LevelOneEighth:
+		return getCellCount(LevelQuarter) * levelOneEighthMesh.Division.Y
+	case LevelFivefold:
+		return getCellCount(Level2) * levelFivefoldMesh.Division.Y
+	case LevelTwofold:
+		return getCellCount(Level2) * levelTwofoldMesh.Division.Y
+	}
+	return 0
+}
+
+// toCell メッシュコードを格子上の位置に変換する。
+func toCell(code MeshCode) (cell, error) {
+	if err := Validate(code); err != nil {
+		return cell{}, err
+	}
+	var digits [maxCodeDigit]byte
+	n := copy(digits[:], code)
+	return parseCell(digits[:n]), nil
+}
+
+// parseCell 検証済みのメッシュコードの各桁から格子上の位置を求める。
+func parseCell(digits []byte) cell {
+	c := cell{
+		y: int(digits[0]-'0')*10 + int(digits[1]-'0'),
+		x: int(digits[2]-'0')*10 + int(digits[3]-'0'),
+	}
+	c.level = Level1
+	if len(digits) >= level2Mesh.Digit {
+		c.y = c.y*level2Mesh.Division.Y + int(digits[4]-'0')
+		c.x = c.x*level2Mesh.Division.X + int(digits[5]-'0')
+		c.level = Level2
+	}
+	switch {
+	case len(digits) == levelFivefoldMesh.Digit:
+		q := int(digits[6] - '1')
+		c.y = c.y*levelFivefoldMesh.Division.Y + q/2
+		c.x = c.x*levelFivefoldMesh.Division.X + q%2
+		c.level = LevelFivefold
+		return c
+	case len(digits) == levelTwofoldMesh.Digit && digits[8] == '5':
+		c.y = c.y*levelTwofoldMesh.Division.Y + int(digits[6]-'0')/2
+		c.x = c.x*levelTwofoldMesh.Division.X + int(digits[7]-'0')/2
+		c.level = LevelTwofold
+		return c
+	}
+	if len(digits) >= level3Mesh.Digit {
+		c.y = c.y*level3Mesh.Division.Y + int(digits[6]-'0')
+		c.x = c.x*level3Mesh.Division.X + int(digits[7]-'0')
+		c.level = Level3
+	}
+	// 2分の1地域メッシュ以下は 1:南西, 2:南東, 3:北西, 4:北東
+	for i, level := range []Level{LevelHalf, LevelQuarter, LevelOneEighth} {
+		if len(digits) <= levelHalfMesh.Digit-1+i {
+			break
+		}
+		q := int(digits[levelHalfMesh.Digit-1+i] - '1')
+		c.y = c.y*2 + q/2
+		c.x = c.x*2 + q%2
+		c.level = level
+	}
+	return c
+}
+
+// formatCell 格子上の位置をメッシュコードの各桁に変換して dst に追加する。
+func formatCell(dst []byte, c cell) ([]byte, error) {
+	n := getCellCount(c.level)
+	if n == 0 {
+		return dst, ErrInvalidLevel
+	}
+	if c.y < 0 || c.x < 0 || c.y >= 100*n || c.x >= 100*n {
+		return dst, ErrInvalidArea
+	}
+	lv1Y, lv1X := c.y/n, c.x/n
+	if !isLevel1Area(lv1Y, lv1X) {
+		return dst, ErrInvalidArea
+	}
+	dst = append(dst, byte('0'+lv1Y/10), byte('0'+lv1Y%10), byte('0'+lv1X/10), byte('0'+lv1X%10))
+	if c.level == Level1 {
+		return dst, nil
+	}
+
+	y, x := c.y%n, c.x%n
+	n /= level2Mesh.Division.Y
+	dst = append(dst, byte('0'+y/n), byte('0'+x/n))
+	y, x = y%n, x%n
+	switch c.level {
+	case Level2:
+		return dst, nil
+	case LevelFivefold:
+		return append(dst, byte('1'+y*2+x)), nil
+	case LevelTwofold:
+		return append(dst, byte('0'+y*2), byte('0'+x*2), '5'), nil
+	}
+
+	n /= level3Mesh.Division.Y
+	dst = append(dst, byte('0'+y/n), byte('0'+x/n))
+	y, x = y%n, x%n
+	for n > 1 {
+		n /= 2
+		dst = append(dst, byte('1'+(y/n)*2+x/n))
+		y, x = y%n, x%n
+	}
+	return dst, nil
+}
+
+// toCode 格子上の位置をメッシュコードに変換する。
+func (c cell) toCode() (MeshCode, error) {
+	var buf [maxCodeDigit]byte
+	b, err := formatCell(buf[:0], c)
+	if err != nil {
+		return "", err
+	}
+	return MeshCode(b), nil
+}
+
+// parent 上位のレベルのメッシュを取得する。
+func (c cell) parent() (cell, bool) {
+	var level Level
+	switch c.level {
+	case Level2:
+		level = Level1
+	case Level3, LevelFivefold, LevelTwofold:
+		level = Level2
+	case LevelHalf:
+		level = Level3
+	case LevelQuarter:
+		level = LevelHalf
+	case LevelOneEighth:
+		level = LevelQuarter
+	default:
+		return cell{}, false
+	}
+	ratio := getCellCount(c.level) / getCellCount(level)
+	return cell{level: level, y: c.y / ratio, x: c.x / ratio}, true
+}
+
+// bounds 格子上の位置から範囲を求める。
+func (c cell) bounds() Bounds {
+	mesh, _ := getMesh(c.level)
+	return Bounds{
+		Min: GeoCode{
+			Latitude:  float64(c.y) * mesh.Distance.Lat,
+			Longitude: 100 + float64(c.x)*mesh.Distance.Lng,
+		},
+		Max: GeoCode{
+			Latitude:  float64(c.y+1) * mesh.Distance.Lat,
+			Longitude: 100 + float64(c.x+1)*mesh.Distance.Lng,
+		},
+	}
+}
+
+// inArea 日本の国土にかかる第1次地域区画に含まれるかを判定する。
+func (c cell) inArea() bool {
+	n := getCellCount(c.level)
+	if n == 0 || c.y < 0 || c.x < 0 {
+		return false
+	}
+	return isLevel1Area(c.y/n, c.x/n)
+}
+
+// neighborOffsets 隣接するメッシュの位置(北から時計回り)
+var neighborOffsets = [8][2]int{
+	{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1},
+}
+
+// isLevel1Area 第1次地域区画が日本の国土にかかる区画であるかを判定する。
+func isLevel1Area(lv1Y, lv1X int) bool {
+	if lv1Y < 0 || lv1Y >= 100 || lv1X < 0 || lv1X >= 100 {
+		return false
+	}
+	return level1Table[lv1Y*100+lv1X]
+}
+
+// level1Table level1Codes を 緯度方向2桁*100+経度方向2桁 で引けるようにしたもの
+var level1Table = func() (table [100 * 100]bool) {
+	for code := range level1Codes {
+		y := int(code[0]-'0')*10 + int(code[1]-'0')
+		x := int(code[2]-'0')*10 + int(code[3]-'0')
+		table[y*100+x] = true
+	}
+	return table
+}()
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// levelColumnNames annotate で追加する列名の接尾辞
+var levelColumnNames = map[japanmesh.Level]string{
+	japanmesh.Level1:         "1",
+	japanmesh.Level2:         "2",
+	japanmesh.Level3:         "3",
+	japanmesh.LevelHalf:      "half",
+	japanmesh.LevelQuarter:   "quarter",
+	japanmesh.LevelOneEighth: "eighth",
+	japanmesh.LevelTwofold:   "2x",
+	japanmesh.LevelFivefold:  "5x",
+}
+
+func runAnnotate(c *cli, args []string) error {
+	fs := c.newFlagSet("annotate", "[file]")
+	latColumn := fs.String("lat", "lat", "latitude column name")
+	lngColumn := fs.String("lng", "lng", "longitude column name")
+	levelList := fs.String("level", string(japanmesh.Level3), "comma-separated mesh levels: 1, 2, 3, 1/2, 1/4, 1/8, 2x or 5x")
+	prefix := fs.String("prefix", "mesh_", "prefix of the appended column names")
+	delimiter := fs.String("delimiter", ",", `field delimiter: "," or "tab"`)
+	encoding := fs.String("encoding", "utf-8", "character encoding of the input and output: utf-8 or shift_jis")
+	drop := fs.Bool("drop", false, "drop rows outside the mesh area instead of leaving the mesh columns empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var targets []japanmesh.Level
+	for _, s := range strings.Split(*levelList, ",") {
+		level, err := japanmesh.ParseLevel(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		targets = append(targets, level)
+	}
+	comma, err := parseDelimiter(*delimiter)
+	if err != nil {
+		return err
+	}
+	in, closeInput, err := c.openInput(fs.Args())
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+	in, err = decodeInput(in, *encoding)
+	if err != nil {
+		return err
+	}
+	out, _ := encodeOutput(c.stdout, *encoding)
+
+	r := csv.NewReader(in)
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+	w := csv.NewWriter(out)
+	w.Comma = comma
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("no header row")
+		}
+		return err
+	}
+	if len(header) > 0 {
+		header[0] = trimBOM(header[0])
+	}
+	latIndex, lngIndex := indexOf(header, *latColumn), indexOf(header, *lngColumn)
+	if latIndex < 0 || lngIndex < 0 {
+		return fmt.Errorf("column %q or %q not found in header", *latColumn, *lngColumn)
+	}
+	row := append([]string(nil), header...)
+	for _, level := range targets {
+		row = append(row, *prefix+levelColumnNames[level])
+	}
+	if err := w.Write(row); err != nil {
+		return err
+	}
+
+	var outside int
+	for {
+		fields, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := r.FieldPos(0)
+		row = append(row[:0], fields...)
+		codes, err := annotateRow(fields, latIndex, lngIndex, targets)
+		if err != nil {
+			outside++
+			fmt.Fprintf(c.stderr, "line %d: %v\n", line, err)
+			if *drop {
+				continue
+			}
+		}
+		row = append(row, codes...)
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	if closer, ok := out.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	if outside > 0 {
+		fmt.Fprintf(c.stderr, "%d rows outside the mesh area or without valid coordinates\n", outside)
+	}
+	return nil
+}
+
+// annotateRow 行の緯度経度から各レベルの地域メッシュコードを求める。
+// 求められない場合は空のメッシュコードとエラーを返す。
+func annotateRow(fields []string, latIndex, lngIndex int, levels []japanmesh.Level) ([]string, error) {
+	codes := make([]string, len(levels))
+	if latIndex >= len(fields) || lngIndex >= len(fields) {
+		return codes, fmt.Errorf("%w: missing coordinates", japanmesh.ErrInvalidGeoCode)
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(fields[latIndex]), 64)
+	lng, err2 := strconv.ParseFloat(strings.TrimSpace(fields[lngIndex]), 64)
+	if err1 != nil || err2 != nil {
+		return codes, fmt.Errorf("%w: %q, %q", japanmesh.ErrInvalidGeoCode, fields[latIndex], fields[lngIndex])
+	}
+	geoCode := japanmesh.GeoCode{Latitude: lat, Longitude: lng}
+	for i, level := range levels {
+		code, err := japanmesh.ToCode(geoCode, level)
+		if err != nil {
+			return make([]string, len(levels)), fmt.Errorf("%w: %v, %v", err, lat, lng)
+		}
+		codes[i] = string(code)
+	}
+	return codes, nil
+}
+
+// openInput 引数のファイル、または引数がない場合は標準入力を開く。
+func (c *cli) openInput(args []string) (io.Reader, func(), error) {
+	switch len(args) {
+	case 0:
+		return c.stdin, func() {}, nil
+	case 1:
+		f, err := os.Open(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, func() { f.Close() }, nil
+	}
+	return nil, nil, fmt.Errorf("too many arguments")
+}
+
+// decodeInput 文字コードに応じて入力を UTF-8 に変換する。
+func decodeInput(in io.Reader, encoding string) (io.Reader, error) {
+	switch normalizeEncoding(encoding) {
+	case "utf_8":
+		return in, nil
+	case "shift_jis":
+		return transform.NewReader(in, japanese.ShiftJIS.NewDecoder()), nil
+	}
+	return nil, fmt.Errorf("unknown encoding %q (utf-8, shift_jis)", encoding)
+}
+
+// encodeOutput 文字コードに応じて UTF-8 の出力を変換する。Shift_JIS の出力は Close で書き出しを終える。
+func encodeOutput(out io.Writer, encoding string) (io.Writer, error) {
+	switch normalizeEncoding(encoding) {
+	case "utf_8":
+		return out, nil
+	case "shift_jis":
+		return transform.NewWriter(out, japanese.ShiftJIS.NewEncoder()), nil
+	}
+	return nil, fmt.Errorf("unknown encoding %q (utf-8, shift_jis)", encoding)
+}
+
+func normalizeEncoding(encoding string) string {
+	switch strings.ToLower(strings.ReplaceAll(encoding, "-", "_")) {
+	case "utf_8", "utf8":
+		return "utf_8"
+	case "shift_jis", "sjis", "cp932":
+		return "shift_jis"
+	}
+	return encoding
+}
+
+func parseDelimiter(s string) (rune, error) {
+	switch s {
+	case ",":
+		return ',', nil
+	case "tab", `\t`, "\t":
+		return '\t', nil
+	}
+	return 0, fmt.Errorf("unknown delimiter %q", s)
+}
+
+// trimBOM UTF-8 の BOM を取り除く。
+func trimBOM(s string) string {
+	return strings.TrimPrefix(s, "\ufeff")
+}
+
+func indexOf(fields []string, name string) int {
+	for i, f := range fields {
+		if strings.TrimSpace(f) == name {
+			return i
+		}
+	}
+	return -1
+}
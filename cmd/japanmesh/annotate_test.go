@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestRunAnnotate(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		want       string
+		wantCode   int
+		wantStderr string
+	}{
+		{
+			name:  "csv",
+			args:  []string{"annotate", "--level", "3,1/2"},
+			stdin: "name,lat,lng\n新宿,35.70078,139.71475\n沖合,10,100\n不明,,\n",
+			want: "name,lat,lng,mesh_3,mesh_half\n" +
+				"新宿,35.70078,139.71475,53394547,533945471\n" +
+				"沖合,10,100,,\n" +
+				"不明,,,,\n",
+			wantStderr: "line 3: invalid area",
+		},
+		{
+			name:  "tsv with custom columns",
+			args:  []string{"annotate", "--delimiter", "tab", "--lat", "緯度", "--lng", "経度", "--prefix", "m", "--level", "2x"},
+			stdin: "緯度\t経度\n35.70078\t139.71475\n",
+			want:  "緯度\t経度\tm2x\n35.70078\t139.71475\t533945465\n",
+		},
+		{
+			name:  "drop",
+			args:  []string{"annotate", "--drop"},
+			stdin: "\ufefflat,lng\n10,100\n35.70078,139.71475\n",
+			want:  "lat,lng,mesh_3\n35.70078,139.71475,53394547\n",
+		},
+		{name: "missing column", args: []string{"annotate"}, stdin: "y,x\n35,139\n", wantCode: 1},
+		{name: "empty", args: []string{"annotate"}, stdin: "", wantCode: 1},
+		{name: "invalid level", args: []string{"annotate", "--level", "3,4"}, stdin: "lat,lng\n", wantCode: 1},
+		{name: "unknown encoding", args: []string{"annotate", "--encoding", "euc-jp"}, stdin: "lat,lng\n", wantCode: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("run() code = %v, want %v, stderr = %v", code, tt.wantCode, stderr.String())
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("run() stdout = %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("run() stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRunAnnotate_ShiftJIS(t *testing.T) {
+	src := "名称,緯度,経度\r\n東京都庁,35.68944,139.69167\r\n"
+	in, err := japanese.ShiftJIS.NewEncoder().String(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"annotate", "--encoding", "shift_jis", "--lat", "緯度", "--lng", "経度"}, strings.NewReader(in), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("run() code = %v, stderr = %v", code, stderr.String())
+	}
+	got, err := japanese.ShiftJIS.NewDecoder().String(stdout.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "名称,緯度,経度,mesh_3\n東京都庁,35.68944,139.69167,53394525\n"; got != want {
+		t.Errorf("run() stdout = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+)
+
+// levelFlagUsage --level の説明
+const levelFlagUsage = "mesh level: 1, 2, 3, 1/2, 1/4, 1/8, 2x or 5x"
+
+// parseGeoCode "緯度 経度" または "緯度,経度" を解析する。
+func parseGeoCode(s string) (japanmesh.GeoCode, error) {
+	fields := splitFields(s)
+	if len(fields) != 2 {
+		return japanmesh.GeoCode{}, fmt.Errorf("%w: %q", japanmesh.ErrInvalidGeoCode, s)
+	}
+	lat, err1 := strconv.ParseFloat(fields[0], 64)
+	lng, err2 := strconv.ParseFloat(fields[1], 64)
+	if err1 != nil || err2 != nil {
+		return japanmesh.GeoCode{}, fmt.Errorf("%w: %q", japanmesh.ErrInvalidGeoCode, s)
+	}
+	return japanmesh.GeoCode{Latitude: lat, Longitude: lng}, nil
+}
+
+func runEncode(c *cli, args []string) error {
+	fs := c.newFlagSet("encode", "[lat lng ...]")
+	level := fs.String("level", string(japanmesh.Level3), levelFlagUsage)
+	format := fs.String("format", "plain", formatFlagUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	l, err := japanmesh.ParseLevel(*level)
+	if err != nil {
+		return err
+	}
+	w, err := newWriter(c.stdout, *format)
+	if err != nil {
+		return err
+	}
+
+	// 引数は緯度と経度の組とする
+	var inputs []string
+	if fs.NArg() > 0 {
+		fields := splitFields(strings.Join(fs.Args(), " "))
+		if len(fields)%2 != 0 {
+			return fmt.Errorf("%w: latitude and longitude must be given in pairs", japanmesh.ErrInvalidGeoCode)
+		}
+		for i := 0; i < len(fields); i += 2 {
+			inputs = append(inputs, fields[i]+" "+fields[i+1])
+		}
+	}
+	err = c.eachInput(inputs, func(line int, input string) error {
+		geoCode, err := parseGeoCode(input)
+		if err != nil {
+			c.reportf("line %d: %v", line, err)
+			return nil
+		}
+		code, err := japanmesh.ToCode(geoCode, l)
+		if err != nil {
+			c.reportf("line %d: %v: %q", line, err, input)
+			return nil
+		}
+		return w.write(record{
+			names:  []string{"lat", "lng", "code"},
+			values: []interface{}{geoCode.Latitude, geoCode.Longitude, code},
+			plain:  []int{2},
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return w.flush()
+}
+
+// eachCode 入力の各行を検証済みの地域メッシュコードとして fn に渡す。
+func (c *cli) eachCode(args []string, fn func(code japanmesh.MeshCode) error) error {
+	return c.eachInput(args, func(line int, input string) error {
+		code := japanmesh.MeshCode(input)
+		if err := japanmesh.Validate(code); err != nil {
+			c.reportf("line %d: %v: %q", line, err, input)
+			return nil
+		}
+		return fn(code)
+	})
+}
+
+// runCodes 地域メッシュコードを入力とするサブコマンドを実行する。
+func runCodes(c *cli, name string, args []string, fn func(w writer, code japanmesh.MeshCode) error) error {
+	fs := c.newFlagSet(name, "[code ...]")
+	format := fs.String("format", "plain", formatFlagUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	w, err := newWriter(c.stdout, *format)
+	if err != nil {
+		return err
+	}
+	if err := c.eachCode(fs.Args(), func(code japanmesh.MeshCode) error { return fn(w, code) }); err != nil {
+		return err
+	}
+	return w.flush()
+}
+
+func runDecode(c *cli, args []string) error {
+	return runCodes(c, "decode", args, func(w writer, code japanmesh.MeshCode) error {
+		level, _ := japanmesh.GetLevel(code)
+		bounds, err := japanmesh.ToBounds(code)
+		if err != nil {
+			return err
+		}
+		center := bounds.Center()
+		return w.write(record{
+			names: []string{"code", "level", "min_lat", "min_lng", "max_lat", "max_lng", "center_lat", "center_lng"},
+			values: []interface{}{
+				code, level,
+				bounds.Min.Latitude, bounds.Min.Longitude, bounds.Max.Latitude, bounds.Max.Longitude,
+				center.Latitude, center.Longitude,
+			},
+		})
+	})
+}
+
+func runLevel(c *cli, args []string) error {
+	return runCodes(c, "level", args, func(w writer, code japanmesh.MeshCode) error {
+		level, _ := japanmesh.GetLevel(code)
+		return w.write(record{names: []string{"code", "level"}, values: []interface{}{code, level}, plain: []int{1}})
+	})
+}
+
+func runChildren(c *cli, args []string) error {
+	return runCodes(c, "children", args, func(w writer, code japanmesh.MeshCode) error {
+		children, err := japanmesh.GetCodes(code)
+		if err != nil {
+			c.reportf("%v: %q", err, code)
+			return nil
+		}
+		for _, child := range children {
+			if err := w.write(record{names: []string{"code", "child"}, values: []interface{}{code, child}, plain: []int{1}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func runSplit(c *cli, args []string) error {
+	return runCodes(c, "split", args, func(w writer, code japanmesh.MeshCode) error {
+		for _, part := range japanmesh.SplitCodeByLevel(code) {
+			level, _ := japanmesh.GetLevel(part)
+			if err := w.write(record{names: []string{"code", "level", "part"}, values: []interface{}{code, level, part}, plain: []int{2}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// coverModes --mode で指定できる判定方法
+var coverModes = map[string]japanmesh.CoverMode{
+	"intersects": japanmesh.CoverIntersects,
+	"contains":   japanmesh.CoverContains,
+	"center":     japanmesh.CoverCenter,
+}
+
+func runCover(c *cli, args []string) error {
+	fs := c.newFlagSet("cover", "[file.geojson]")
+	level := fs.String("level", string(japanmesh.Level3), levelFlagUsage)
+	mode := fs.String("mode", "intersects", "intersects (meshes overlapping the feature), contains (meshes inside the feature) or center (meshes whose center is inside the feature)")
+	format := fs.String("format", "csv", formatFlagUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	l, err := japanmesh.ParseLevel(*level)
+	if err != nil {
+		return err
+	}
+	m, ok := coverModes[*mode]
+	if !ok {
+		return fmt.Errorf("unknown mode %q (intersects, contains, center)", *mode)
+	}
+	w, err := newWriter(c.stdout, *format)
+	if err != nil {
+		return err
+	}
+	in, closeInput, err := c.openInput(fs.Args())
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+	features, err := readFeatures(in)
+	if err != nil {
+		return err
+	}
+
+	// 全地物の属性名を列とする。
+	// メッシュコードの列名と重なる code 属性は、他の属性名と重ならなくなるまで "property_" を付けた列名とする
+	keys := map[string]struct{}{}
+	for _, f := range features {
+		for key := range f.Properties {
+			keys[key] = struct{}{}
+		}
+	}
+	properties := make([]string, 0, len(keys))
+	for key := range keys {
+		properties = append(properties, key)
+	}
+	sort.Strings(properties)
+	names := []string{"code"}
+	for _, key := range properties {
+		name := key
+		if name == "code" {
+			name = "property_code"
+			for _, ok := keys[name]; ok; _, ok = keys[name] {
+				name = "property_" + name
+			}
+		}
+		names = append(names, name)
+	}
+
+	for i, f := range features {
+		codes, err := japanmesh.Cover(f.Geometry, l, m)
+		if err != nil {
+			c.reportf("feature %d: %v", i, err)
+			continue
+		}
+		for _, code := range codes {
+			values := []interface{}{code}
+			for _, key := range properties {
+				values = append(values, f.Properties[key])
+			}
+			if err := w.write(record{names: names, values: values}); err != nil {
+				return err
+			}
+		}
+	}
+	return w.flush()
+}
+
+// readFeatures FeatureCollection、Feature、または Geometry の GeoJSON を読み込む。
+func readFeatures(r io.Reader) ([]*geojson.Feature, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var object struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("invalid geojson: %w", err)
+	}
+	switch object.Type {
+	case "FeatureCollection":
+		fc, err := geojson.UnmarshalFeatureCollection(data)
+		if err != nil {
+			return nil, err
+		}
+		return fc.Features, nil
+	case "Feature":
+		f, err := geojson.UnmarshalFeature(data)
+		if err != nil {
+			return nil, err
+		}
+		return []*geojson.Feature{f}, nil
+	}
+	g, err := geojson.UnmarshalGeometry(data)
+	if err != nil {
+		return nil, err
+	}
+	return []*geojson.Feature{geojson.NewFeature(g)}, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunCover(t *testing.T) {
+	// 53394547 の範囲
+	square := `{"type":"Polygon","coordinates":[[[139.7125,35.7],[139.725,35.7],[139.725,35.70833333333333],[139.7125,35.70833333333333],[139.7125,35.7]]]}`
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		want       string
+		wantCode   int
+		wantStderr string
+	}{
+		{
+			name: "feature collection",
+			args: []string{"cover", "--level", "1/2"},
+			stdin: `{"type":"FeatureCollection","features":[` +
+				`{"type":"Feature","properties":{"city":"13104","pop":12,"code":"x"},"geometry":` + square + `},` +
+				`{"type":"Feature","properties":{"name":"点"},"geometry":{"type":"Point","coordinates":[139.71475,35.70078]}}]}`,
+			want: "code,city,property_code,name,pop\n" +
+				"533945471,13104,x,,12\n" +
+				"533945472,13104,x,,12\n" +
+				"533945473,13104,x,,12\n" +
+				"533945474,13104,x,,12\n" +
+				"533945471,,,点,\n",
+		},
+		{
+			name:  "code property collision",
+			args:  []string{"cover", "--format", "json"},
+			stdin: `{"type":"Feature","properties":{"code":"13104","property_code":"a"},"geometry":` + square + `}`,
+			want:  `{"code":"53394547","property_property_code":"13104","property_code":"a"}` + "\n",
+		},
+		{
+			name:  "feature json",
+			args:  []string{"cover", "--format", "json"},
+			stdin: `{"type":"Feature","properties":{"city":"13104"},"geometry":` + square + `}`,
+			want:  `{"code":"53394547","city":"13104"}` + "\n",
+		},
+		{
+			name:  "geometry contains",
+			args:  []string{"cover", "--level", "1/4", "--mode", "contains", "--format", "plain"},
+			stdin: `{"type":"Polygon","coordinates":[[[139.7125,35.7],[139.71875,35.7],[139.71875,35.704],[139.7125,35.704],[139.7125,35.7]]]}`,
+			want:  "5339454711\n5339454712\n",
+		},
+		{
+			name:       "unsupported geometry",
+			args:       []string{"cover"},
+			stdin:      `{"type":"Feature","properties":{},"geometry":{"type":"LineString","coordinates":[[139,35],[140,36]]}}`,
+			want:       "",
+			wantCode:   1,
+			wantStderr: "feature 0: invalid geometry",
+		},
+		{name: "invalid json", args: []string{"cover"}, stdin: "{", wantCode: 1},
+		{name: "unknown mode", args: []string{"cover", "--mode", "within"}, stdin: square, wantCode: 1},
+		{name: "invalid level", args: []string{"cover", "--level", "4"}, stdin: square, wantCode: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("run() code = %v, want %v, stderr = %v", code, tt.wantCode, stderr.String())
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("run() stdout = %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("run() stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
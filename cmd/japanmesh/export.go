@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// featureWriter export の出力形式ごとの書き出し
+type featureWriter interface {
+	// write 地域メッシュの範囲の地物を書き出す。names と values は値の列名と値
+	write(code japanmesh.MeshCode, feature *geojson.Feature, names []string, values []interface{}) error
+	close() error
+}
+
+func runExport(c *cli, args []string) error {
+	fs := c.newFlagSet("export", "[file]")
+	format := fs.String("format", "geojson", "output format: geojson (FeatureCollection), ndjson (one Feature per line), wkt (CSV) or kml")
+	codeColumn := fs.String("code", "code", "mesh code column name; without a header row the first column is used")
+	delimiter := fs.String("delimiter", ",", `field delimiter of the input: "," or "tab"`)
+	encoding := fs.String("encoding", "utf-8", "character encoding of the input: utf-8 or shift_jis")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	comma, err := parseDelimiter(*delimiter)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(c.stdout)
+	var fw featureWriter
+	switch *format {
+	case "geojson":
+		fw = &featureCollectionWriter{w: bw}
+	case "ndjson":
+		fw = &ndjsonWriter{w: bw}
+	case "wkt":
+		fw = &wktWriter{w: csv.NewWriter(bw)}
+	case "kml":
+		fw = &kmlWriter{w: bw}
+	default:
+		return fmt.Errorf("unknown format %q (geojson, ndjson, wkt, kml)", *format)
+	}
+	in, closeInput, err := c.openInput(fs.Args())
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+	if in, err = decodeInput(in, *encoding); err != nil {
+		return err
+	}
+
+	r := csv.NewReader(in)
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	first := true
+	codeIndex := 0
+	var names []string
+	for {
+		fields, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := r.FieldPos(0)
+		if first {
+			first = false
+			fields[0] = trimBOM(fields[0])
+			// 最初の行に列名がある場合は見出しとし、ない場合は最初の列をメッシュコードとする
+			if i := indexOf(fields, *codeColumn); i >= 0 {
+				codeIndex = i
+				for j, f := range fields {
+					if j != i {
+						names = append(names, strings.TrimSpace(f))
+					}
+				}
+				continue
+			}
+			for j := 1; j < len(fields); j++ {
+				names = append(names, "value"+strconv.Itoa(j))
+			}
+		}
+		if codeIndex >= len(fields) {
+			c.reportf("line %d: %v: missing mesh code", line, japanmesh.ErrInvalidMeshCode)
+			continue
+		}
+		code := japanmesh.MeshCode(strings.TrimSpace(fields[codeIndex]))
+		var values []interface{}
+		for j, f := range fields {
+			if j != codeIndex && len(values) < len(names) {
+				values = append(values, parseValue(f))
+			}
+		}
+		for len(values) < len(names) {
+			values = append(values, nil)
+		}
+		properties := map[string]interface{}{"code": string(code)}
+		for i, name := range names {
+			properties[name] = values[i]
+		}
+		if err := japanmesh.Validate(code); err != nil {
+			c.reportf("line %d: %v: %q", line, err, code)
+			continue
+		}
+		feature, err := japanmesh.ToGeoJSON(code, properties)
+		if err != nil {
+			c.reportf("line %d: %v: %q", line, err, code)
+			continue
+		}
+		if err := fw.write(code, feature, names, values); err != nil {
+			return err
+		}
+	}
+	if err := fw.close(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// parseValue 数値として解釈できる値は数値、空の値は nil とする。
+func parseValue(s string) interface{} {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(s, "xXpP") {
+		return v
+	}
+	return s
+}
+
+// ring 地物の外周の座標(経度, 緯度)
+func ring(feature *geojson.Feature) [][]float64 {
+	if feature.Geometry == nil || len(feature.Geometry.Polygon) == 0 {
+		return nil
+	}
+	return feature.Geometry.Polygon[0]
+}
+
+type featureCollectionWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func (f *featureCollectionWriter) write(code japanmesh.MeshCode, feature *geojson.Feature, _ []string, _ []interface{}) error {
+	if f.count == 0 {
+		f.w.WriteString(`{"type":"FeatureCollection","features":[` + "\n")
+	} else {
+		f.w.WriteString(",\n")
+	}
+	f.count++
+	feature.ID = string(code)
+	b, err := json.Marshal(feature)
+	if err != nil {
+		return err
+	}
+	_, err = f.w.Write(b)
+	return err
+}
+
+func (f *featureCollectionWriter) close() error {
+	if f.count == 0 {
+		f.w.WriteString(`{"type":"FeatureCollection","features":[`)
+	} else {
+		f.w.WriteString("\n")
+	}
+	_, err := f.w.WriteString("]}\n")
+	return err
+}
+
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+func (n *ndjsonWriter) write(code japanmesh.MeshCode, feature *geojson.Feature, _ []string, _ []interface{}) error {
+	feature.ID = string(code)
+	b, err := json.Marshal(feature)
+	if err != nil {
+		return err
+	}
+	n.w.Write(b)
+	return n.w.WriteByte('\n')
+}
+
+func (n *ndjsonWriter) close() error {
+	return nil
+}
+
+type wktWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (k *wktWriter) write(code japanmesh.MeshCode, feature *geojson.Feature, names []string, values []interface{}) error {
+	if !k.header {
+		k.header = true
+		if err := k.w.Write(append([]string{"code", "wkt"}, names...)); err != nil {
+			return err
+		}
+	}
+	points := make([]string, 0, 5)
+	for _, p := range ring(feature) {
+		points = append(points, formatValue(p[0])+" "+formatValue(p[1]))
+	}
+	row := []string{string(code), "POLYGON((" + strings.Join(points, ", ") + "))"}
+	for _, v := range values {
+		if v == nil {
+			row = append(row, "")
+			continue
+		}
+		row = append(row, formatValue(v))
+	}
+	return k.w.Write(row)
+}
+
+func (k *wktWriter) close() error {
+	k.w.Flush()
+	return k.w.Error()
+}
+
+type kmlWriter struct {
+	w     *bufio.Writer
+	start bool
+}
+
+func (k *kmlWriter) write(code japanmesh.MeshCode, feature *geojson.Feature, names []string, values []interface{}) error {
+	k.begin()
+	k.w.WriteString("<Placemark><name>")
+	xml.EscapeText(k.w, []byte(code))
+	k.w.WriteString("</name>")
+	if len(names) > 0 {
+		k.w.WriteString("<ExtendedData>")
+		for i, name := range names {
+			k.w.WriteString(`<Data name="`)
+			xml.EscapeText(k.w, []byte(name))
+			k.w.WriteString(`"><value>`)
+			if values[i] != nil {
+				xml.EscapeText(k.w, []byte(formatValue(values[i])))
+			}
+			k.w.WriteString("</value></Data>")
+		}
+		k.w.WriteString("</ExtendedData>")
+	}
+	k.w.WriteString("<Polygon><outerBoundaryIs><LinearRing><coordinates>")
+	for i, p := range ring(feature) {
+		if i > 0 {
+			k.w.WriteByte(' ')
+		}
+		k.w.WriteString(formatValue(p[0]) + "," + formatValue(p[1]))
+	}
+	_, err := k.w.WriteString("</coordinates></LinearRing></outerBoundaryIs></Polygon></Placemark>\n")
+	return err
+}
+
+func (k *kmlWriter) begin() {
+	if k.start {
+		return
+	}
+	k.start = true
+	k.w.WriteString(xml.Header)
+	k.w.WriteString(`<kml xmlns="http://www.opengis.net/kml/2.2"><Document>` + "\n")
+}
+
+func (k *kmlWriter) close() error {
+	k.begin()
+	_, err := k.w.WriteString("</Document></kml>\n")
+	return err
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+func TestRunExport_GeoJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	stdin := "code,population,name\n53394547,120,新宿\n5339452,,x\n53394,1,invalid\n"
+	code := run([]string{"export"}, strings.NewReader(stdin), &stdout, &stderr)
+	if code != 1 || !strings.Contains(stderr.String(), `line 4: invalid meshcode: "53394"`) {
+		t.Errorf("run() code = %v, stderr = %v", code, stderr.String())
+	}
+	fc, err := geojson.UnmarshalFeatureCollection(stdout.Bytes())
+	if err != nil {
+		t.Fatalf("UnmarshalFeatureCollection() error = %v, stdout = %v", err, stdout.String())
+	}
+	if len(fc.Features) != 2 {
+		t.Fatalf("features = %v, want 2", len(fc.Features))
+	}
+	f := fc.Features[0]
+	if f.ID != "53394547" || f.Properties["population"] != 120.0 || f.Properties["name"] != "新宿" || f.Properties["code"] != "53394547" {
+		t.Errorf("feature = %+v", f)
+	}
+	if got := f.Geometry.Polygon[0][2]; got[0] != 139.7125 {
+		t.Errorf("feature coordinates = %v", f.Geometry.Polygon)
+	}
+	if fc.Features[1].Properties["population"] != nil {
+		t.Errorf("empty value = %v, want nil", fc.Features[1].Properties["population"])
+	}
+}
+
+func TestRunExport_Formats(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		check func(t *testing.T, out string)
+	}{
+		{
+			name:  "empty geojson",
+			args:  []string{"export"},
+			stdin: "",
+			check: func(t *testing.T, out string) {
+				if out != "{\"type\":\"FeatureCollection\",\"features\":[]}\n" {
+					t.Errorf("stdout = %q", out)
+				}
+			},
+		},
+		{
+			name:  "ndjson tab",
+			args:  []string{"export", "--format", "ndjson", "--delimiter", "tab"},
+			stdin: "53394547\t5\n53394548\t6\n",
+			check: func(t *testing.T, out string) {
+				lines := strings.Split(strings.TrimSpace(out), "\n")
+				if len(lines) != 2 {
+					t.Fatalf("lines = %v, want 2", len(lines))
+				}
+				var f geojson.Feature
+				if err := json.Unmarshal([]byte(lines[1]), &f); err != nil {
+					t.Fatal(err)
+				}
+				if f.Properties["value1"] != 6.0 {
+					t.Errorf("properties = %v", f.Properties)
+				}
+			},
+		},
+		{
+			name:  "wkt",
+			args:  []string{"export", "--format", "wkt", "--code", "mesh"},
+			stdin: "name,mesh\na,5339\n",
+			check: func(t *testing.T, out string) {
+				want := "code,wkt,name\n5339,\"POLYGON((140 35.99999999999999, 139 35.99999999999999, 139 35.33333333333333, 140 35.33333333333333, 140 35.99999999999999))\",a\n"
+				if out != want {
+					t.Errorf("stdout = %q, want %q", out, want)
+				}
+			},
+		},
+		{
+			name:  "kml",
+			args:  []string{"export", "--format", "kml"},
+			stdin: "code,name\n5339,<a&b>\n",
+			check: func(t *testing.T, out string) {
+				if !strings.Contains(out, "<name>5339</name>") || !strings.Contains(out, "&lt;a&amp;b&gt;") || !strings.Contains(out, "<coordinates>140,35.99999999999999 139,35.99999999999999 ") {
+					t.Errorf("stdout = %v", out)
+				}
+				dec := xml.NewDecoder(strings.NewReader(out))
+				for {
+					if _, err := dec.Token(); err == io.EOF {
+						break
+					} else if err != nil {
+						t.Fatalf("invalid xml: %v", err)
+					}
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr); code != 0 {
+				t.Fatalf("run() code = %v, stderr = %v", code, stderr.String())
+			}
+			tt.check(t, stdout.String())
+		})
+	}
+}
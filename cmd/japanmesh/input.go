@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+)
+
+// eachInput 引数、または引数がない場合は標準入力の空行以外の各行を fn に渡す。
+// line は標準入力の行番号(引数の場合は引数の順番)。
+func (c *cli) eachInput(args []string, fn func(line int, input string) error) error {
+	if len(args) > 0 {
+		for i, arg := range args {
+			if err := fn(i+1, arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	scanner := bufio.NewScanner(c.stdin)
+	line := 0
+	for scanner.Scan() {
+		line++
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			continue
+		}
+		if err := fn(line, input); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// splitFields 空白、カンマまたはタブで区切られた項目に分割する。
+func splitFields(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
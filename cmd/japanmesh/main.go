@@ -0,0 +1,115 @@
+// Command japanmesh 地域メッシュコードを変換・検索するコマンドラインツール。
+//
+//	japanmesh encode --level 3 35.7 139.71
+//	japanmesh decode 53394547
+//	echo 53394547 | japanmesh children --format csv
+//
+// 引数を指定しない場合は標準入力から1行ずつ読み込む。
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command サブコマンド
+type command struct {
+	summary string
+	run     func(c *cli, args []string) error
+}
+
+// commands サブコマンドの一覧
+var commands = map[string]command{
+	"encode":   {summary: "緯度経度から地域メッシュコードを求める", run: runEncode},
+	"decode":   {summary: "地域メッシュコードの範囲と中心を求める", run: runDecode},
+	"level":    {summary: "地域メッシュコードのレベルを求める", run: runLevel},
+	"children": {summary: "1つ下のレベルの地域メッシュコードを求める", run: runChildren},
+	"split":    {summary: "地域メッシュコードを上位のレベルごとに分割する", run: runSplit},
+	"annotate": {summary: "CSV/TSV の緯度経度の列から地域メッシュコードの列を追加する", run: runAnnotate},
+	"export":   {summary: "地域メッシュコードを GeoJSON、KML、WKT に変換する", run: runExport},
+	"cover":    {summary: "GeoJSON の地物を覆う地域メッシュコードを求める", run: runCover},
+	"serve":    {summary: "地域メッシュコードを変換する HTTP API を起動する", run: runServe},
+}
+
+// errInvalidInput 一部の入力を変換できなかったことを表す。個々のエラーは標準エラー出力に書き出す。
+var errInvalidInput = errors.New("some inputs could not be processed")
+
+// cli 入出力
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	// 標準エラー出力に書き出した、入力ごとのエラーの数
+	errors int
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run サブコマンドを実行し、終了コードを返す。
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "japanmesh: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+	err := cmd.run(c, args[1:])
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case err != nil:
+		fmt.Fprintf(stderr, "japanmesh %s: %v\n", args[0], err)
+		return 1
+	case c.errors > 0:
+		fmt.Fprintf(stderr, "japanmesh %s: %v (%d)\n", args[0], errInvalidInput, c.errors)
+		return 1
+	}
+	return 0
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: japanmesh <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'japanmesh <command> -h' for details.")
+}
+
+// newFlagSet サブコマンドのフラグを生成する。
+func (c *cli) newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: japanmesh %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// reportf 入力ごとのエラーを標準エラー出力に書き出し、処理を続ける。
+func (c *cli) reportf(format string, a ...interface{}) {
+	c.errors++
+	fmt.Fprintf(c.stderr, format+"\n", a...)
+}
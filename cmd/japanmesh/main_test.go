@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		want     string
+		wantCode int
+	}{
+		{name: "encode", args: []string{"encode", "--level", "3", "35.7", "139.71"}, want: "53394546\n"},
+		{name: "encode default level", args: []string{"encode", "35.70078,139.71475"}, want: "53394547\n"},
+		{name: "encode stdin csv", args: []string{"encode", "--level", "1/2", "--format", "csv"}, stdin: "35.70078 139.71475\n\n35.70078,139.71475\n",
+			want: "lat,lng,code\n35.70078,139.71475,533945471\n35.70078,139.71475,533945471\n"},
+		{name: "encode out of area", args: []string{"encode"}, stdin: "10 100\n35.70078 139.71475\n", want: "53394547\n", wantCode: 1},
+		{name: "encode odd args", args: []string{"encode", "35.7"}, wantCode: 1},
+		{name: "encode invalid level", args: []string{"encode", "--level", "4", "35.7", "139.71"}, wantCode: 1},
+		{name: "decode", args: []string{"decode", "5339"}, want: "5339\t1\t35.33333333333333\t139\t35.99999999999999\t140\t35.66666666666666\t139.5\n"},
+		{name: "decode json", args: []string{"decode", "--format", "json", "533945005"},
+			want: `{"code":"533945005","level":"2x","min_lat":35.666666666666664,"min_lng":139.625,"max_lat":35.68333333333333,"max_lng":139.65,"center_lat":35.675,"center_lng":139.6375}` + "\n"},
+		{name: "level", args: []string{"level", "53394547", "5339452"}, want: "3\n5x\n"},
+		{name: "level invalid", args: []string{"level", "53394", "5339"}, want: "1\n", wantCode: 1},
+		{name: "children", args: []string{"children", "533945471"}, want: "5339454711\n5339454712\n5339454713\n5339454714\n"},
+		{name: "children of one eighth", args: []string{"children", "--format", "csv", "53394547111"}, want: "", wantCode: 1},
+		{name: "split", args: []string{"split", "--format", "csv"}, stdin: "533945471\n",
+			want: "code,level,part\n533945471,1,5339\n533945471,2,533945\n533945471,3,53394547\n533945471,1/2,533945471\n"},
+		{name: "serve unexpected args", args: []string{"serve", "extra"}, wantCode: 1},
+		{name: "serve invalid max batch", args: []string{"serve", "--max-batch", "0"}, wantCode: 1},
+		{name: "serve invalid addr", args: []string{"serve", "--addr", "invalid:addr:1"}, wantCode: 1},
+		{name: "serve missing values", args: []string{"serve", "--values", "/nonexistent/values.csv"}, wantCode: 1},
+		{name: "unknown command", args: []string{"unknown"}, wantCode: 2},
+		{name: "no command", args: nil, wantCode: 2},
+		{name: "help", args: []string{"encode", "-h"}, wantCode: 0},
+		{name: "unknown format", args: []string{"level", "--format", "xml", "5339"}, wantCode: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("run() code = %v, want %v, stderr = %v", code, tt.wantCode, stderr.String())
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("run() stdout = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// record 出力する1件分の項目
+type record struct {
+	names  []string
+	values []interface{}
+	// plain 形式で出力する項目の添字。nil の場合はすべての項目
+	plain []int
+}
+
+// writer 出力形式ごとの書き出し
+type writer interface {
+	write(r record) error
+	flush() error
+}
+
+// newWriter 出力形式に対応する writer を生成する。
+func newWriter(w io.Writer, format string) (writer, error) {
+	switch format {
+	case "plain":
+		return &plainWriter{w: bufio.NewWriter(w)}, nil
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case "json":
+		return &jsonWriter{w: bufio.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q (plain, csv, json)", format)
+}
+
+// formatFlagUsage --format の説明
+const formatFlagUsage = "output format: plain, csv or json (one object per line)"
+
+// plainWriter 項目をタブ区切りで書き出す。
+type plainWriter struct {
+	w *bufio.Writer
+}
+
+func (p *plainWriter) write(r record) error {
+	indexes := r.plain
+	if indexes == nil {
+		indexes = make([]int, len(r.values))
+		for i := range indexes {
+			indexes[i] = i
+		}
+	}
+	for i, index := range indexes {
+		if i > 0 {
+			p.w.WriteByte('\t')
+		}
+		p.w.WriteString(formatValue(r.values[index]))
+	}
+	return p.w.WriteByte('\n')
+}
+
+func (p *plainWriter) flush() error {
+	return p.w.Flush()
+}
+
+// csvWriter 最初の行に項目名を書き出す。
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (c *csvWriter) write(r record) error {
+	if !c.header {
+		c.header = true
+		if err := c.w.Write(r.names); err != nil {
+			return err
+		}
+	}
+	fields := make([]string, len(r.values))
+	for i, v := range r.values {
+		fields[i] = formatValue(v)
+	}
+	return c.w.Write(fields)
+}
+
+func (c *csvWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonWriter 1行に1つの JSON オブジェクトを、項目の順序を保って書き出す。
+type jsonWriter struct {
+	w *bufio.Writer
+}
+
+func (j *jsonWriter) write(r record) error {
+	j.w.WriteByte('{')
+	for i, name := range r.names {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		value, err := json.Marshal(r.values[i])
+		if err != nil {
+			return err
+		}
+		j.w.Write(key)
+		j.w.WriteByte(':')
+		j.w.Write(value)
+	}
+	j.w.WriteString("}\n")
+	return nil
+}
+
+func (j *jsonWriter) flush() error {
+	return j.w.Flush()
+}
+
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(v)
+		return string(b)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		return strings.Join(v, " ")
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(v)
+}
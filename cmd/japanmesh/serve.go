@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+	"github.com/keitaro1020/go-japanmesh/server"
+)
+
+func runServe(c *cli, args []string) error {
+	fs := c.newFlagSet("serve", "")
+	addr := fs.String("addr", ":8080", "address to listen on")
+	maxBatch := fs.Int("max-batch", 10000, "maximum number of points in a batch encode request")
+	valuesFile := fs.String("values", "", "CSV/TSV file of mesh codes and values to draw on /tiles")
+	codeColumn := fs.String("code", "code", "mesh code column name of --values; without a header row the first column is used")
+	valueColumn := fs.String("value", "value", "value column name of --values; without a header row the second column is used")
+	delimiter := fs.String("delimiter", ",", `field delimiter of --values: "," or "tab"`)
+	encoding := fs.String("encoding", "utf-8", "character encoding of --values: utf-8 or shift_jis")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	if *maxBatch < 1 {
+		return fmt.Errorf("invalid --max-batch: %d", *maxBatch)
+	}
+	opts := []server.Option{server.WithMaxBatchSize(*maxBatch)}
+	if *valuesFile != "" {
+		comma, err := parseDelimiter(*delimiter)
+		if err != nil {
+			return err
+		}
+		values, err := loadValues(*valuesFile, *codeColumn, *valueColumn, comma, *encoding)
+		if err != nil {
+			return err
+		}
+		layer, err := server.NewValueLayer(values)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, server.WithValueLayer(layer))
+		fmt.Fprintf(c.stderr, "japanmesh serve: loaded %d values from %s\n", len(values), *valuesFile)
+	}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.NewHandler(opts...),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(c.stderr, "japanmesh serve: listening on %s\n", *addr)
+	return srv.ListenAndServe()
+}
+
+// loadValues メッシュコードと数値の列を持つ CSV/TSV を読み込む。値が空の行は読み飛ばす。
+func loadValues(name, codeColumn, valueColumn string, comma rune, encoding string) (map[japanmesh.MeshCode]float64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	in, err := decodeInput(f, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	r := csv.NewReader(in)
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	values := map[japanmesh.MeshCode]float64{}
+	first := true
+	codeIndex, valueIndex := 0, 1
+	for {
+		fields, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		if first {
+			first = false
+			fields[0] = trimBOM(fields[0])
+			// 最初の行にメッシュコードの列名がある場合は見出しとする
+			if i := indexOf(fields, codeColumn); i >= 0 {
+				codeIndex, valueIndex = i, indexOf(fields, valueColumn)
+				if valueIndex < 0 {
+					return nil, fmt.Errorf("%s: column %q not found", name, valueColumn)
+				}
+				continue
+			}
+		}
+		if codeIndex >= len(fields) || valueIndex >= len(fields) {
+			return nil, fmt.Errorf("%s: line %d: missing column", name, line)
+		}
+		s := strings.TrimSpace(fields[valueIndex])
+		if s == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: invalid value %q", name, line, s)
+		}
+		values[japanmesh.MeshCode(strings.TrimSpace(fields[codeIndex]))] = v
+	}
+}
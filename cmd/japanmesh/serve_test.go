@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+)
+
+func TestLoadValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		args    []string
+		comma   rune
+		want    map[japanmesh.MeshCode]float64
+		wantErr bool
+	}{
+		{
+			name:    "header",
+			content: "\ufeffname,code,value\n新宿,53394547,120\n空,53394548,\n",
+			want:    map[japanmesh.MeshCode]float64{"53394547": 120},
+		},
+		{
+			name:    "custom columns tsv",
+			content: "人口\tメッシュ\n1.5\t533945471\n",
+			args:    []string{"メッシュ", "人口"},
+			comma:   '\t',
+			want:    map[japanmesh.MeshCode]float64{"533945471": 1.5},
+		},
+		{
+			name:    "no header",
+			content: "53394547,120\n53394548,80\n",
+			want:    map[japanmesh.MeshCode]float64{"53394547": 120, "53394548": 80},
+		},
+		{name: "missing value column", content: "code,population\n53394547,120\n", wantErr: true},
+		{name: "invalid value", content: "53394547,many\n", wantErr: true},
+		{name: "missing column", content: "53394547\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "values.csv")
+			if err := os.WriteFile(name, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			codeColumn, valueColumn := "code", "value"
+			if tt.args != nil {
+				codeColumn, valueColumn = tt.args[0], tt.args[1]
+			}
+			comma := tt.comma
+			if comma == 0 {
+				comma = ','
+			}
+			got, err := loadValues(name, codeColumn, valueColumn, comma, "utf-8")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package japanmesh
+
+import "sort"
+
+// compactLevels Compact で上位のメッシュにまとめるレベル(下位から順)
+var compactLevels = []Level{LevelOneEighth, LevelQuarter, LevelHalf, Level3, Level2}
+
+// Compact 配下のメッシュがすべて含まれる場合は上位のメッシュにまとめ、異なるレベルが混在する少ない数のメッシュコードにする。
+// 上位のメッシュが含まれる場合、その配下のメッシュは取り除く。
+// 統合地域メッシュや不正なメッシュコードはそのまま残す。結果は昇順に並べる。
+func Compact(codes MeshCodes) MeshCodes {
+	set := make(map[MeshCode]struct{}, len(codes))
+	var others MeshCodes
+	for _, code := range codes {
+		level, err := GetLevel(code)
+		if err != nil || !isStandardLevel(level) || Validate(code) != nil {
+			others = append(others, code)
+			continue
+		}
+		set[code] = struct{}{}
+	}
+
+	// 上位のメッシュが含まれるメッシュを取り除く
+	for code := range set {
+		ancestors := SplitCodeByLevel(code)
+		for _, ancestor := range ancestors[:len(ancestors)-1] {
+			if _, ok := set[ancestor]; ok {
+				delete(set, code)
+				break
+			}
+		}
+	}
+
+	// 下位のレベルから順に、配下のメッシュがすべて含まれる上位のメッシュにまとめる
+	for _, level := range compactLevels {
+		counts := make(map[MeshCode]int)
+		for code := range set {
+			if l, _ := GetLevel(code); l == level {
+				parent, _ := GetParent(code)
+				counts[parent]++
+			}
+		}
+		mesh, _ := getMesh(level)
+		for parent, count := range counts {
+			if count < mesh.Division.X*mesh.Division.Y {
+				continue
+			}
+			children, err := GetCodes(parent)
+			if err != nil {
+				continue
+			}
+			for _, child := range children {
+				delete(set, child)
+			}
+			set[parent] = struct{}{}
+		}
+	}
+
+	result := make(MeshCodes, 0, len(set)+len(others))
+	for code := range set {
+		result = append(result, code)
+	}
+	result = append(result, dedupeCodes(others)...)
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// Uncompact 異なるレベルが混在するメッシュコードを、指定したレベルのメッシュコードに展開する。
+// 指定したレベルより下位のメッシュや統合地域メッシュが含まれる場合は ErrInvalidLevel を返す。結果は昇順に並べる。
+func Uncompact(codes MeshCodes, level Level) (MeshCodes, error) {
+	var result MeshCodes
+	for _, code := range codes {
+		if err := Validate(code); err != nil {
+			return nil, err
+		}
+		descendants, err := getDescendants(code, level)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, descendants...)
+	}
+	result = dedupeCodes(result)
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+// dedupeCodes 重複するメッシュコードを取り除く。
+func dedupeCodes(codes MeshCodes) MeshCodes {
+	seen := make(map[MeshCode]struct{}, len(codes))
+	result := make(MeshCodes, 0, len(codes))
+	for _, code := range codes {
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		result = append(result, code)
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package japanmesh
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	lv3Codes, _ := GetCodes("533945")
+	lvQuarterCodes, _ := GetCodes("533946001")
+	tests := []struct {
+		name  string
+		codes MeshCodes
+		want  MeshCodes
+	}{
+		{
+			name:  "level1-2 -> level3",
+			codes: MeshCodes{"533945471", "533945472", "533945473", "533945474", "533945481"},
+			want:  MeshCodes{"53394547", "533945481"},
+		},
+		{
+			name:  "level3 -> level2",
+			codes: append(MeshCodes{"53394600"}, lv3Codes...),
+			want:  MeshCodes{"533945", "53394600"},
+		},
+		{
+			name:  "cascade level1-4 -> level3",
+			codes: append(MeshCodes{"533946002", "533946003", "533946004"}, lvQuarterCodes...),
+			want:  MeshCodes{"53394600"},
+		},
+		{
+			name:  "descendant of included mesh",
+			codes: MeshCodes{"53394547112", "533945", "53394547", "533945"},
+			want:  MeshCodes{"533945"},
+		},
+		{
+			name:  "integrated and invalid",
+			codes: MeshCodes{"5339452", "1", "1", "53394547"},
+			want:  MeshCodes{"1", "5339452", "53394547"},
+		},
+		{
+			name:  "empty",
+			codes: nil,
+			want:  MeshCodes{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compact(tt.codes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUncompact(t *testing.T) {
+	tests := []struct {
+		name    string
+		codes   MeshCodes
+		level   Level
+		want    MeshCodes
+		wantErr bool
+	}{
+		{
+			name:  "level3 -> level1-2",
+			codes: MeshCodes{"53394547", "533945481", "533945471"},
+			level: LevelHalf,
+			want:  MeshCodes{"533945471", "533945472", "533945473", "533945474", "533945481"},
+		},
+		{name: "finer level", codes: MeshCodes{"533945471"}, level: Level3, want: nil, wantErr: true},
+		{name: "integrated", codes: MeshCodes{"5339452"}, level: Level3, want: nil, wantErr: true},
+		{name: "invalid", codes: MeshCodes{"1"}, level: Level3, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Uncompact(tt.codes, tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Uncompact() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Uncompact() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Compact したメッシュコードは元に戻せる
+	codes, _ := GetCodes("533945")
+	codes = append(codes, "53394600")
+	got, err := Uncompact(Compact(codes), Level3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, codes) {
+		t.Errorf("Uncompact(Compact()) got = %v, want %v", got, codes)
+	}
+}
@@ -47,11 +47,16 @@ const (
 	LevelHalf      Level = "1/2"
 	LevelQuarter   Level = "1/4"
 	LevelOneEighth Level = "1/8"
+	// 統合地域メッシュ
+	LevelTwofold  Level = "2x"
+	LevelFivefold Level = "5x"
 )
 
 var (
 	ErrInvalidArea     = errors.New("invalid area")
 	ErrInvalidMeshCode = errors.New("invalid meshcode")
+	ErrInvalidGeoCode  = errors.New("invalid geocode")
+	ErrInvalidLevel    = errors.New("invalid level")
 )
 
 // 第1次地域区画
@@ -155,6 +160,36 @@ var levelOneEighthMesh = Mesh{
 	},
 }
 
+// 5倍地域メッシュ(統合地域メッシュ)
+// 第2次地域区画を縦横2等分したもの。第2次地域区画のコードに 1:南西, 2:南東, 3:北西, 4:北東 の1桁を加える。
+var levelFivefoldMesh = Mesh{
+	Digit: 7,
+	Division: Division{
+		X: 2,
+		Y: 2,
+	},
+	// 緯度: 2分30秒, 経度: 3分45秒
+	Distance: Distance{
+		Lat: float64(2.5) / float64(60),
+		Lng: float64(3.75) / float64(60),
+	},
+}
+
+// 2倍地域メッシュ(統合地域メッシュ)
+// 第2次地域区画を縦横5等分したもの。第2次地域区画のコードに、南西端の基準地域メッシュの緯度方向・経度方向の数字(偶数)と 5 を加える。
+var levelTwofoldMesh = Mesh{
+	Digit: 9,
+	Division: Division{
+		X: 5,
+		Y: 5,
+	},
+	// 緯度: 1分, 経度: 1分30秒
+	Distance: Distance{
+		Lat: float64(1) / float64(60),
+		Lng: float64(1.5) / float64(60),
+	},
+}
+
 // 第１次地域区画の全メッシュコード
 // https://www.e-stat.go.jp/pdf/gis/primary_mesh_jouhou.pdf
 var level1Codes = map[Level1Code]interface{}{
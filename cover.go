@@ -0,0 +1,216 @@
+package japanmesh
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+var ErrInvalidGeometry = errors.New("invalid geometry")
+
+// CoverMode Cover で図形に含めるメッシュの判定方法
+type CoverMode int
+
+const (
+	// CoverIntersects 図形と重なる部分があるメッシュを含める(境界で接するだけのメッシュは含めない)。
+	CoverIntersects CoverMode = iota
+	// CoverContains 全体が図形に含まれるメッシュを含める。
+	CoverContains
+	// CoverCenter 中心が図形に含まれるメッシュを含める。
+	CoverCenter
+)
+
+// coverEpsilon メッシュの境界上にある図形の辺を、メッシュと重ならないとみなす誤差(度)
+const coverEpsilon = 1e-12
+
+// coverEdge 図形の辺(経度, 緯度)
+type coverEdge struct {
+	x0, y0, x1, y1 float64
+}
+
+// Cover 図形を覆う指定したレベルの地域メッシュコードを、メッシュコードの昇順で取得する。
+//
+// Polygon、MultiPolygon、Point、MultiPoint に対応し、座標は経度、緯度の順とする。
+// Point は mode によらず点を含むメッシュとする。
+// 日本の国土にかからない第1次地域区画のメッシュは含めない。
+func Cover(geometry *geojson.Geometry, level Level, mode CoverMode) (MeshCodes, error) {
+	if _, ok := getMesh(level); !ok {
+		return nil, ErrInvalidLevel
+	}
+	if geometry == nil {
+		return nil, ErrInvalidGeometry
+	}
+	switch geometry.Type {
+	case geojson.GeometryPoint:
+		return coverPoints([][]float64{geometry.Point}, level)
+	case geojson.GeometryMultiPoint:
+		return coverPoints(geometry.MultiPoint, level)
+	case geojson.GeometryPolygon:
+		return coverPolygons([][][][]float64{geometry.Polygon}, level, mode)
+	case geojson.GeometryMultiPolygon:
+		return coverPolygons(geometry.MultiPolygon, level, mode)
+	}
+	return nil, ErrInvalidGeometry
+}
+
+func coverPoints(points [][]float64, level Level) (MeshCodes, error) {
+	var codes MeshCodes
+	for _, p := range points {
+		if len(p) < 2 {
+			return nil, ErrInvalidGeometry
+		}
+		code, err := ToCode(GeoCode{Latitude: p[1], Longitude: p[0]}, level)
+		if err != nil {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	codes = dedupeCodes(codes)
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes, nil
+}
+
+func coverPolygons(polygons [][][][]float64, level Level, mode CoverMode) (MeshCodes, error) {
+	// 多角形ごとの辺と範囲
+	edges := make([][]coverEdge, len(polygons))
+	bounds := make([]Bounds, 0, len(polygons))
+	for i, polygon := range polygons {
+		minLng, minLat, maxLng, maxLat := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		for _, ring := range polygon {
+			if len(ring) < 3 {
+				return nil, ErrInvalidGeometry
+			}
+			for j := range ring {
+				p, q := ring[j], ring[(j+1)%len(ring)]
+				if len(p) < 2 || len(q) < 2 {
+					return nil, ErrInvalidGeometry
+				}
+				edges[i] = append(edges[i], coverEdge{x0: p[0], y0: p[1], x1: q[0], y1: q[1]})
+				minLng, maxLng = math.Min(minLng, p[0]), math.Max(maxLng, p[0])
+				minLat, maxLat = math.Min(minLat, p[1]), math.Max(maxLat, p[1])
+			}
+		}
+		if !math.IsInf(minLng, 1) {
+			bounds = append(bounds, Bounds{Min: GeoCode{Latitude: minLat, Longitude: minLng}, Max: GeoCode{Latitude: maxLat, Longitude: maxLng}})
+		}
+	}
+
+	// 離れた多角形の間のメッシュを調べないよう、多角形ごとの範囲にかかる行と列だけを調べる
+	mesh, _ := getMesh(level)
+	n := getCellCount(level)
+	columns := make(map[int][][2]int)
+	for _, b := range bounds {
+		y0 := maxInt(0, int(math.Floor(b.Min.Latitude/mesh.Distance.Lat)))
+		y1 := minInt(100*n-1, int(math.Floor(b.Max.Latitude/mesh.Distance.Lat)))
+		x0 := maxInt(0, int(math.Floor((b.Min.Longitude-100)/mesh.Distance.Lng)))
+		x1 := minInt(100*n-1, int(math.Floor((b.Max.Longitude-100)/mesh.Distance.Lng)))
+		if x0 > x1 {
+			continue
+		}
+		for y := y0; y <= y1; y++ {
+			columns[y] = append(columns[y], [2]int{x0, x1})
+		}
+	}
+	rows := make([]int, 0, len(columns))
+	for y := range columns {
+		rows = append(rows, y)
+	}
+	sort.Ints(rows)
+
+	var codes MeshCodes
+	var rowEdges []coverEdge
+	crossings := make([][]float64, len(polygons))
+	for _, y := range rows {
+		row := cell{level: level, y: y}.bounds()
+		centerLat := (row.Min.Latitude + row.Max.Latitude) / 2
+
+		// 行と重なる辺と、行の中心の緯線と辺が交わる経度
+		rowEdges = rowEdges[:0]
+		for i, es := range edges {
+			crossings[i] = crossings[i][:0]
+			for _, e := range es {
+				if math.Max(e.y0, e.y1) > row.Min.Latitude && math.Min(e.y0, e.y1) < row.Max.Latitude {
+					rowEdges = append(rowEdges, e)
+				}
+				if (e.y0 > centerLat) != (e.y1 > centerLat) {
+					crossings[i] = append(crossings[i], e.x0+(centerLat-e.y0)*(e.x1-e.x0)/(e.y1-e.y0))
+				}
+			}
+			sort.Float64s(crossings[i])
+		}
+
+		for _, r := range columns[y] {
+			for x := r[0]; x <= r[1]; x++ {
+				c := cell{level: level, y: y, x: x}
+				if !c.inArea() {
+					continue
+				}
+				b := c.bounds()
+				inside := false
+				centerLng := (b.Min.Longitude + b.Max.Longitude) / 2
+				for _, xs := range crossings {
+					// 中心より西で交わる数が奇数であれば多角形の内側
+					if sort.SearchFloat64s(xs, centerLng)%2 == 1 {
+						inside = true
+						break
+					}
+				}
+				var include bool
+				switch mode {
+				case CoverCenter:
+					include = inside
+				case CoverContains:
+					include = inside && !edgesCross(rowEdges, b)
+				default:
+					include = inside || edgesCross(rowEdges, b)
+				}
+				if include {
+					code, _ := c.toCode()
+					codes = append(codes, code)
+				}
+			}
+		}
+	}
+	// 範囲が重なる多角形では同じメッシュを複数回調べる
+	codes = dedupeCodes(codes)
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes, nil
+}
+
+// edgesCross いずれかの辺が範囲の内部を通るかを判定する。範囲の境界上のみを通る辺は含めない。
+func edgesCross(edges []coverEdge, b Bounds) bool {
+	minX, minY := b.Min.Longitude+coverEpsilon, b.Min.Latitude+coverEpsilon
+	maxX, maxY := b.Max.Longitude-coverEpsilon, b.Max.Latitude-coverEpsilon
+	for _, e := range edges {
+		if math.Max(e.x0, e.x1) <= minX || math.Min(e.x0, e.x1) >= maxX {
+			continue
+		}
+		// Liang-Barsky 法で辺を範囲に切り取る
+		t0, t1 := 0.0, 1.0
+		dx, dy := e.x1-e.x0, e.y1-e.y0
+		clip := func(p, q float64) bool {
+			if p == 0 {
+				return q > 0
+			}
+			r := q / p
+			if p < 0 {
+				if r > t1 {
+					return false
+				}
+				t0 = math.Max(t0, r)
+			} else {
+				if r < t0 {
+					return false
+				}
+				t1 = math.Min(t1, r)
+			}
+			return true
+		}
+		if clip(-dx, e.x0-minX) && clip(dx, maxX-e.x0) && clip(-dy, e.y0-minY) && clip(dy, maxY-e.y0) && t0 < t1 {
+			return true
+		}
+	}
+	return false
+}
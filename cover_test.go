@@ -0,0 +1,168 @@
+package japanmesh
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+func boundsPolygon(b Bounds) [][]float64 {
+	return [][]float64{
+		{b.Min.Longitude, b.Min.Latitude}, {b.Max.Longitude, b.Min.Latitude},
+		{b.Max.Longitude, b.Max.Latitude}, {b.Min.Longitude, b.Max.Latitude},
+		{b.Min.Longitude, b.Min.Latitude},
+	}
+}
+
+func TestCover_Mesh(t *testing.T) {
+	feature, _ := ToGeoJSON("53394547", nil)
+	for _, mode := range []CoverMode{CoverIntersects, CoverContains, CoverCenter} {
+		got, err := Cover(feature.Geometry, Level3, mode)
+		if err != nil {
+			t.Fatalf("Cover() error = %v", err)
+		}
+		if want := (MeshCodes{"53394547"}); !reflect.DeepEqual(got, want) {
+			t.Errorf("Cover() mode %v got = %v, want %v", mode, got, want)
+		}
+		got, _ = Cover(feature.Geometry, LevelHalf, mode)
+		if want := (MeshCodes{"533945471", "533945472", "533945473", "533945474"}); !reflect.DeepEqual(got, want) {
+			t.Errorf("Cover() mode %v half got = %v, want %v", mode, got, want)
+		}
+	}
+}
+
+func TestCover_Modes(t *testing.T) {
+	// 53394547 の南西の角から、東に基準地域メッシュ1.4個分、北に1.6個分の範囲
+	b, _ := ToBounds("53394547")
+	b.Max.Longitude = b.Min.Longitude + level3Mesh.Distance.Lng*1.4
+	b.Max.Latitude = b.Min.Latitude + level3Mesh.Distance.Lat*1.6
+	geometry := geojson.NewPolygonGeometry([][][]float64{boundsPolygon(b)})
+	tests := []struct {
+		mode CoverMode
+		want MeshCodes
+	}{
+		{mode: CoverIntersects, want: MeshCodes{"53394547", "53394548", "53394557", "53394558"}},
+		{mode: CoverContains, want: MeshCodes{"53394547"}},
+		{mode: CoverCenter, want: MeshCodes{"53394547", "53394557"}},
+	}
+	for _, tt := range tests {
+		got, err := Cover(geometry, Level3, tt.mode)
+		if err != nil {
+			t.Fatalf("Cover() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Cover() mode %v got = %v, want %v", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestCover_Hole(t *testing.T) {
+	outer, _ := ToBounds("533945")
+	hole, _ := ToBounds("53394547")
+	geometry := geojson.NewPolygonGeometry([][][]float64{boundsPolygon(outer), boundsPolygon(hole)})
+	for _, mode := range []CoverMode{CoverIntersects, CoverContains, CoverCenter} {
+		got, _ := Cover(geometry, Level3, mode)
+		if len(got) != 99 {
+			t.Errorf("Cover() mode %v len = %v, want 99", mode, len(got))
+		}
+		for _, code := range got {
+			if code == "53394547" {
+				t.Errorf("Cover() mode %v contains hole", mode)
+			}
+		}
+	}
+}
+
+func TestCover_AcrossLevel1(t *testing.T) {
+	// 5339 と 5340 の境界(東経140度)をまたぐ
+	geometry := geojson.NewMultiPolygonGeometry(
+		[][][]float64{{{139.99, 35.70}, {140.01, 35.70}, {140.01, 35.701}, {139.99, 35.70}}},
+		[][][]float64{{{135.5, 34.7}, {135.5001, 34.7}, {135.5001, 34.7001}, {135.5, 34.7}}},
+	)
+	got, err := Cover(geometry, Level3, CoverIntersects)
+	if err != nil {
+		t.Fatalf("Cover() error = %v", err)
+	}
+	want := MeshCodes{"52350440", "53394749", "53404040"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Cover() got = %v, want %v", got, want)
+	}
+}
+
+func TestCover_SparseMultiPolygon(t *testing.T) {
+	// 利尻島と与那国島の1/8地域メッシュ。多角形の間の範囲は調べない
+	a, _ := ToBounds("68411200111")
+	b, _ := ToBounds("36225700444")
+	geometry := geojson.NewMultiPolygonGeometry([][][]float64{boundsPolygon(a)}, [][][]float64{boundsPolygon(b)})
+	for _, mode := range []CoverMode{CoverIntersects, CoverContains, CoverCenter} {
+		got, err := Cover(geometry, LevelOneEighth, mode)
+		if err != nil {
+			t.Fatalf("Cover() error = %v", err)
+		}
+		if want := (MeshCodes{"36225700444", "68411200111"}); !reflect.DeepEqual(got, want) {
+			t.Errorf("Cover(%v) got = %v, want %v", mode, got, want)
+		}
+	}
+}
+
+func TestCover_Points(t *testing.T) {
+	geometry := geojson.NewMultiPointGeometry([]float64{139.71475, 35.70078}, []float64{139.71475, 35.70078}, []float64{100, 10})
+	got, err := Cover(geometry, Level3, CoverContains)
+	if err != nil {
+		t.Fatalf("Cover() error = %v", err)
+	}
+	if want := (MeshCodes{"53394547"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cover() got = %v, want %v", got, want)
+	}
+}
+
+func TestCover_Subset(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		ring := [][]float64{}
+		for j := 0; j < 5; j++ {
+			ring = append(ring, []float64{139.6 + r.Float64()*0.2, 35.6 + r.Float64()*0.2})
+		}
+		ring = append(ring, ring[0])
+		geometry := geojson.NewPolygonGeometry([][][]float64{ring})
+		intersects, _ := Cover(geometry, Level3, CoverIntersects)
+		contains, _ := Cover(geometry, Level3, CoverContains)
+		center, _ := Cover(geometry, Level3, CoverCenter)
+		set, _ := NewMeshSet(intersects...)
+		for _, code := range append(contains, center...) {
+			if !set.Contains(code) {
+				t.Fatalf("Cover() %v is not in intersects", code)
+			}
+		}
+		centers, _ := NewMeshSet(center...)
+		for _, code := range contains {
+			if !centers.Contains(code) {
+				t.Fatalf("Cover() %v is not in center", code)
+			}
+		}
+	}
+}
+
+func TestCover_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry *geojson.Geometry
+		level    Level
+		wantErr  error
+	}{
+		{name: "nil", geometry: nil, level: Level3, wantErr: ErrInvalidGeometry},
+		{name: "line", geometry: geojson.NewLineStringGeometry([][]float64{{139, 35}, {140, 36}}), level: Level3, wantErr: ErrInvalidGeometry},
+		{name: "short ring", geometry: geojson.NewPolygonGeometry([][][]float64{{{139, 35}, {140, 36}}}), level: Level3, wantErr: ErrInvalidGeometry},
+		{name: "invalid level", geometry: geojson.NewPointGeometry([]float64{139, 35}), level: "4", wantErr: ErrInvalidLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Cover(tt.geometry, tt.level, CoverIntersects); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Cover() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+// Package estat は e-Stat で公開されている地域メッシュ統計(国勢調査 地域メッシュ統計など)のファイルを読み込む。
+//
+// 地域メッシュ統計は第1次地域区画ごとに Shift_JIS の CSV/TXT として配布されており、
+// 1行目に KEY_CODE などの項目名、2行目に項目の日本語説明が入っている。
+package estat
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// 地域メッシュ統計の固定列
+const (
+	ColumnKeyCode  = "KEY_CODE"
+	ColumnHtksyori = "HTKSYORI"
+	ColumnHtksaki  = "HTKSAKI"
+	ColumnGassan   = "GASSAN"
+)
+
+var (
+	ErrNoKeyCode      = errors.New("estat: KEY_CODE column not found")
+	ErrDuplicatedCode = errors.New("estat: duplicated KEY_CODE")
+)
+
+// Concealment 秘匿処理(HTKSYORI)の区分
+type Concealment int
+
+const (
+	// 秘匿なし
+	ConcealmentNone Concealment = 0
+	// 秘匿対象(値は HTKSAKI のメッシュに合算されている)
+	ConcealmentTarget Concealment = 1
+	// 合算先(GASSAN のメッシュの値を含む)
+	ConcealmentMerged Concealment = 2
+)
+
+// Column 統計項目
+type Column struct {
+	// 項目名(例: T000876001)
+	Name string
+	// 項目の説明(例: 人口（総数）)
+	Description string
+}
+
+// Value 統計値
+type Value struct {
+	Float float64
+	// 値が存在する場合 true
+	Valid bool
+	// 秘匿値(`*`)の場合 true
+	Secret bool
+}
+
+// Record 1メッシュ分の統計値
+type Record struct {
+	Code japanmesh.MeshCode
+	// 秘匿処理
+	Concealment Concealment
+	// 秘匿先のメッシュコード
+	ConcealedTo japanmesh.MeshCode
+	// 合算したメッシュコード
+	MergedWith japanmesh.MeshCodes
+	// Columns と同じ順序の統計値
+	Values []Value
+}
+
+// Table ファイル全体の統計値
+type Table struct {
+	Columns []Column
+	Records map[japanmesh.MeshCode]*Record
+}
+
+// ParseError 行番号付きの読み込みエラー
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("estat: line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Reader 地域メッシュ統計ファイルを1行ずつ読み込む。
+type Reader struct {
+	r       *csv.Reader
+	line    int
+	columns []Column
+	// 各固定列の位置(存在しない場合は -1)
+	keyCode, htksyori, htksaki, gassan int
+	// 統計項目の列位置
+	valueIndexes []int
+}
+
+// NewReader Shift_JIS のファイルを読み込む Reader を生成する。
+func NewReader(r io.Reader) *Reader {
+	return NewUTF8Reader(transform.NewReader(r, japanese.ShiftJIS.NewDecoder()))
+}
+
+// NewUTF8Reader UTF-8 に変換済みのファイルを読み込む Reader を生成する。
+func NewUTF8Reader(r io.Reader) *Reader {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	return &Reader{r: cr, keyCode: -1, htksyori: -1, htksaki: -1, gassan: -1}
+}
+
+// Columns 統計項目の一覧を取得する。
+func (r *Reader) Columns() ([]Column, error) {
+	if err := r.readHeader(); err != nil {
+		return nil, err
+	}
+	return r.columns, nil
+}
+
+// Read 次の1メッシュ分の統計値を読み込む。ファイルの終端では io.EOF を返す。
+func (r *Reader) Read() (*Record, error) {
+	if err := r.readHeader(); err != nil {
+		return nil, err
+	}
+	fields, err := r.readLine()
+	if err != nil {
+		return nil, err
+	}
+	return r.parseRecord(fields)
+}
+
+// ReadAll ファイル全体を読み込み、メッシュコードをキーにした統計値を取得する。
+func ReadAll(r io.Reader) (*Table, error) {
+	return readAll(NewReader(r))
+}
+
+func readAll(reader *Reader) (*Table, error) {
+	columns, err := reader.Columns()
+	if err != nil {
+		return nil, err
+	}
+	table := &Table{
+		Columns: columns,
+		Records: make(map[japanmesh.MeshCode]*Record),
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := table.Records[record.Code]; ok {
+			return nil, &ParseError{Line: reader.line, Err: ErrDuplicatedCode}
+		}
+		table.Records[record.Code] = record
+	}
+	return table, nil
+}
+
+func (r *Reader) readHeader() error {
+	if r.columns != nil {
+		return nil
+	}
+	names, err := r.readLine()
+	if err == io.EOF {
+		return &ParseError{Line: r.line, Err: ErrNoKeyCode}
+	}
+	if err != nil {
+		return err
+	}
+	descriptions, err := r.readLine()
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	columns := make([]Column, 0, len(names))
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		switch name {
+		case ColumnKeyCode:
+			r.keyCode = i
+			continue
+		case ColumnHtksyori:
+			r.htksyori = i
+			continue
+		case ColumnHtksaki:
+			r.htksaki = i
+			continue
+		case ColumnGassan:
+			r.gassan = i
+			continue
+		}
+		var description string
+		if i < len(descriptions) {
+			description = strings.TrimSpace(descriptions[i])
+		}
+		columns = append(columns, Column{Name: name, Description: description})
+		r.valueIndexes = append(r.valueIndexes, i)
+	}
+	if r.keyCode < 0 {
+		return &ParseError{Line: 1, Err: ErrNoKeyCode}
+	}
+	r.columns = columns
+	return nil
+}
+
+func (r *Reader) readLine() ([]string, error) {
+	for {
+		fields, err := r.r.Read()
+		if err != nil {
+			return nil, err
+		}
+		r.line, _ = r.r.FieldPos(0)
+		if len(fields) == 1 && strings.TrimSpace(fields[0]) == "" {
+			// 空行は読み飛ばす
+			continue
+		}
+		return fields, nil
+	}
+}
+
+func (r *Reader) parseRecord(fields []string) (*Record, error) {
+	code, err := r.parseCode(field(fields, r.keyCode))
+	if err != nil {
+		return nil, err
+	}
+	record := &Record{
+		Code:   code,
+		Values: make([]Value, len(r.valueIndexes)),
+	}
+
+	if s := field(fields, r.htksyori); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, &ParseError{Line: r.line, Err: err}
+		}
+		record.Concealment = Concealment(n)
+	}
+	if s := field(fields, r.htksaki); s != "" {
+		if record.ConcealedTo, err = r.parseCode(s); err != nil {
+			return nil, err
+		}
+	}
+	if s := field(fields, r.gassan); s != "" {
+		for _, c := range strings.Split(s, ";") {
+			if c = strings.TrimSpace(c); c == "" {
+				continue
+			}
+			merged, err := r.parseCode(c)
+			if err != nil {
+				return nil, err
+			}
+			record.MergedWith = append(record.MergedWith, merged)
+		}
+	}
+
+	for i, index := range r.valueIndexes {
+		s := field(fields, index)
+		switch s {
+		case "":
+			// 該当なし
+		case "*":
+			record.Values[i].Secret = true
+		default:
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, &ParseError{Line: r.line, Err: fmt.Errorf("%s: %w", r.columns[i].Name, err)}
+			}
+			record.Values[i] = Value{Float: f, Valid: true}
+		}
+	}
+	return record, nil
+}
+
+func (r *Reader) parseCode(s string) (japanmesh.MeshCode, error) {
+	code := japanmesh.MeshCode(s)
+	if err := japanmesh.Validate(code); err != nil {
+		return "", &ParseError{Line: r.line, Err: fmt.Errorf("%q: %w", s, err)}
+	}
+	return code, nil
+}
+
+func field(fields []string, index int) string {
+	if index < 0 || index >= len(fields) {
+		return ""
+	}
+	return strings.TrimSpace(fields[index])
+}
@@ -0,0 +1,97 @@
+package estat
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func toShiftJIS(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := japanese.ShiftJIS.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestReadAll(t *testing.T) {
+	src := "KEY_CODE,HTKSYORI,HTKSAKI,GASSAN,T000876001,T000876002\r\n" +
+		",,,,人口（総数）,　男\r\n" +
+		"533945471,0,,,120,58\r\n" +
+		"533945472,1,533945471,,*,*\r\n" +
+		"533945473,2,,533945472;533945474,30,\r\n"
+
+	got, err := ReadAll(bytes.NewReader(toShiftJIS(t, src)))
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	wantColumns := []Column{
+		{Name: "T000876001", Description: "人口（総数）"},
+		{Name: "T000876002", Description: "男"},
+	}
+	if !reflect.DeepEqual(got.Columns, wantColumns) {
+		t.Errorf("ReadAll() columns = %v, want %v", got.Columns, wantColumns)
+	}
+
+	wantRecords := map[japanmesh.MeshCode]*Record{
+		"533945471": {
+			Code:   "533945471",
+			Values: []Value{{Float: 120, Valid: true}, {Float: 58, Valid: true}},
+		},
+		"533945472": {
+			Code:        "533945472",
+			Concealment: ConcealmentTarget,
+			ConcealedTo: "533945471",
+			Values:      []Value{{Secret: true}, {Secret: true}},
+		},
+		"533945473": {
+			Code:        "533945473",
+			Concealment: ConcealmentMerged,
+			MergedWith:  japanmesh.MeshCodes{"533945472", "533945474"},
+			Values:      []Value{{Float: 30, Valid: true}, {}},
+		},
+	}
+	if !reflect.DeepEqual(got.Records, wantRecords) {
+		t.Errorf("ReadAll() records = %v, want %v", got.Records, wantRecords)
+	}
+}
+
+func TestReadAllIntegratedMesh(t *testing.T) {
+	// 統合地域メッシュ対応前は ErrInvalidMeshCode になっていた
+	src := "KEY_CODE,T000876001\r\n,人口\r\n5339454,1\r\n"
+	got, err := ReadAll(bytes.NewReader(toShiftJIS(t, src)))
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if _, ok := got.Records["5339454"]; !ok {
+		t.Errorf("ReadAll() records = %v, want 5339454", got.Records)
+	}
+}
+
+func TestReadAllError(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr error
+	}{
+		{name: "no key code", src: "CODE,T000876001\r\n,人口\r\n", wantErr: ErrNoKeyCode},
+		{name: "empty", src: "", wantErr: ErrNoKeyCode},
+		{name: "invalid meshcode", src: "KEY_CODE,T000876001\r\n,人口\r\n53394,1\r\n", wantErr: japanmesh.ErrInvalidMeshCode},
+		{name: "invalid area", src: "KEY_CODE,T000876001\r\n,人口\r\n00000000,1\r\n", wantErr: japanmesh.ErrInvalidArea},
+		{name: "duplicated", src: "KEY_CODE,T000876001\r\n,人口\r\n53394547,1\r\n53394547,2\r\n", wantErr: ErrDuplicatedCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadAll(bytes.NewReader(toShiftJIS(t, tt.src)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReadAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package japanmesh
+
+import "math"
+
+// GRS80 楕円体
+const (
+	// 長半径(m)
+	grs80SemiMajorAxis = 6378137
+	// 扁平率
+	grs80Flattening = 1 / 298.257222101
+)
+
+// Bounds 地域メッシュの範囲
+type Bounds struct {
+	// 南西端
+	Min GeoCode
+	// 北東端
+	Max GeoCode
+}
+
+// Center 範囲の中心の緯度経度を取得する。
+func (b Bounds) Center() GeoCode {
+	return GeoCode{
+		Latitude:  (b.Min.Latitude + b.Max.Latitude) / 2,
+		Longitude: (b.Min.Longitude + b.Max.Longitude) / 2,
+	}
+}
+
+// Contains 緯度経度が範囲内(南端・西端を含み、北端・東端を含まない)にあるかを判定する。
+func (b Bounds) Contains(geoCode GeoCode) bool {
+	return geoCode.Latitude >= b.Min.Latitude && geoCode.Latitude < b.Max.Latitude &&
+		geoCode.Longitude >= b.Min.Longitude && geoCode.Longitude < b.Max.Longitude
+}
+
+// Area 範囲の GRS80 楕円体上の面積(m²)を取得する。
+func (b Bounds) Area() float64 {
+	e2 := grs80Flattening * (2 - grs80Flattening)
+	e := math.Sqrt(e2)
+	semiMinorAxis := grs80SemiMajorAxis * (1 - grs80Flattening)
+	// 赤道から緯度 lat までの、経度1ラジアンあたりの面積
+	zone := func(lat float64) float64 {
+		sin := math.Sin(lat * math.Pi / 180)
+		return semiMinorAxis * semiMinorAxis / 2 *
+			(sin/(1-e2*sin*sin) + math.Log((1+e*sin)/(1-e*sin))/(2*e))
+	}
+	return (b.Max.Longitude - b.Min.Longitude) * math.Pi / 180 * (zone(b.Max.Latitude) - zone(b.Min.Latitude))
+}
+
+// GetArea 指定した地域メッシュコードの GRS80 楕円体上の面積(m²)を取得する。
+func GetArea(code MeshCode) (float64, error) {
+	bounds, err := ToBounds(code)
+	if err != nil {
+		return 0, err
+	}
+	return bounds.Area(), nil
+}
+
+// GeodesicDistance 2点間の GRS80 楕円体上の測地線距離(m)を Vincenty の式で求める。
+func GeodesicDistance(a, b GeoCode) float64 {
+	if a == b {
+		return 0
+	}
+	f := float64(grs80Flattening)
+	semiMinorAxis := grs80SemiMajorAxis * (1 - f)
+	rad := math.Pi / 180
+	l := (b.Longitude - a.Longitude) * rad
+	u1 := math.Atan((1 - f) * math.Tan(a.Latitude*rad))
+	u2 := math.Atan((1 - f) * math.Tan(b.Latitude*rad))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	lambda := l
+	var sinSigma, cosSigma, sigma, cos2Alpha, cos2SigmaM float64
+	for i := 0; i < 100; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cos2Alpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+		c := f / 16 * cos2Alpha * (4 + f*(4-3*cos2Alpha))
+		prev := lambda
+		lambda = l + (1-c)*f*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) < 1e-12 {
+			break
+		}
+	}
+	u2b := cos2Alpha * (grs80SemiMajorAxis*grs80SemiMajorAxis - semiMinorAxis*semiMinorAxis) / (semiMinorAxis * semiMinorAxis)
+	aa := 1 + u2b/16384*(4096+u2b*(-768+u2b*(320-175*u2b)))
+	bb := u2b / 1024 * (256 + u2b*(-128+u2b*(74-47*u2b)))
+	deltaSigma := bb * sinSigma * (cos2SigmaM + bb/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		bb/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+	return semiMinorAxis * aa * (sigma - deltaSigma)
+}
+
+// Nearest 範囲内で緯度経度に最も近い点を取得する。範囲内の点はそのまま返す。
+func (b Bounds) Nearest(geoCode GeoCode) GeoCode {
+	return GeoCode{
+		Latitude:  math.Max(b.Min.Latitude, math.Min(b.Max.Latitude, geoCode.Latitude)),
+		Longitude: math.Max(b.Min.Longitude, math.Min(b.Max.Longitude, geoCode.Longitude)),
+	}
+}
+
+// DistanceTo 緯度経度から範囲までの測地線距離(m)を取得する。範囲内の点は0とする。
+func (b Bounds) DistanceTo(geoCode GeoCode) float64 {
+	return GeodesicDistance(geoCode, b.Nearest(geoCode))
+}
@@ -0,0 +1,95 @@
+package japanmesh
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetArea(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    MeshCode
+		want    float64
+		wantErr bool
+	}{
+		// GRS80 楕円体上で M・N・cosφ を数値積分した値
+		{name: "level1", code: "5339", want: 6.697192582719e9, wantErr: false},
+		{name: "level3", code: "53394547", want: 1.045959236914e6, wantErr: false},
+		{name: "level3 hokkaido", code: "64414277", want: 942658.4924212, wantErr: false},
+		{name: "invalid", code: "1", want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetArea(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetArea() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if math.Abs(got-tt.want) > tt.want*1e-9 {
+				t.Errorf("GetArea() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetArea_SumOfChildren(t *testing.T) {
+	parent, _ := GetArea("533945")
+	var sum float64
+	codes, _ := GetCodes("533945")
+	for _, code := range codes {
+		area, _ := GetArea(code)
+		sum += area
+	}
+	if math.Abs(sum-parent) > 1e-3 {
+		t.Errorf("GetArea() sum of children = %v, want %v", sum, parent)
+	}
+}
+
+func TestBounds_Center(t *testing.T) {
+	bounds, _ := ToBounds("53394547")
+	got := bounds.Center()
+	want := GeoCode{Latitude: 35.704166666666666, Longitude: 139.71875}
+	if math.Abs(got.Latitude-want.Latitude) > 1e-12 || math.Abs(got.Longitude-want.Longitude) > 1e-12 {
+		t.Errorf("Bounds.Center() got = %v, want %v", got, want)
+	}
+	if !bounds.Contains(got) || bounds.Contains(bounds.Max) || !bounds.Contains(bounds.Min) {
+		t.Errorf("Bounds.Contains() got unexpected result")
+	}
+}
+
+func TestGeodesicDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a    GeoCode
+		b    GeoCode
+		want float64
+	}{
+		// GRS80 の赤道上の経度1度の長さと、赤道から緯度1度までの子午線弧長
+		{name: "equator", a: GeoCode{Latitude: 0, Longitude: 135}, b: GeoCode{Latitude: 0, Longitude: 136}, want: 111319.4908},
+		{name: "meridian", a: GeoCode{Latitude: 0, Longitude: 135}, b: GeoCode{Latitude: 1, Longitude: 135}, want: 110574.3886},
+		{name: "same", a: GeoCode{Latitude: 35, Longitude: 139}, b: GeoCode{Latitude: 35, Longitude: 139}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GeodesicDistance(tt.a, tt.b); math.Abs(got-tt.want) > 1e-3 {
+				t.Errorf("GeodesicDistance() got = %v, want %v", got, tt.want)
+			}
+			if got, back := GeodesicDistance(tt.a, tt.b), GeodesicDistance(tt.b, tt.a); math.Abs(got-back) > 1e-6 {
+				t.Errorf("GeodesicDistance() not symmetric: %v, %v", got, back)
+			}
+		})
+	}
+}
+
+func TestBounds_DistanceTo(t *testing.T) {
+	bounds, _ := ToBounds("53394547")
+	if got := bounds.DistanceTo(bounds.Center()); got != 0 {
+		t.Errorf("Bounds.DistanceTo() inside got = %v, want 0", got)
+	}
+	// 真北にある点までの距離は北端からの子午線弧長に等しい
+	north := GeoCode{Latitude: bounds.Max.Latitude + 0.01, Longitude: bounds.Center().Longitude}
+	want := GeodesicDistance(GeoCode{Latitude: bounds.Max.Latitude, Longitude: north.Longitude}, north)
+	if got := bounds.DistanceTo(north); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Bounds.DistanceTo() north got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,444 @@
+package japanmesh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidGeoTIFF  = errors.New("invalid geotiff")
+	ErrEmptyGeoTIFF    = errors.New("empty geotiff")
+	ErrGeoTIFFTooLarge = errors.New("geotiff too large")
+)
+
+// maxGeoTIFFPixels WriteGeoTIFF で書き出せる画素数の上限。
+// 画像の大きさとその後ろに置く IFD の位置を uint32 で記録できる範囲に収める。
+const maxGeoTIFFPixels = 1 << 28
+
+// GeoTIFF のタグ
+const (
+	tiffTagImageWidth                = 256
+	tiffTagImageLength               = 257
+	tiffTagBitsPerSample             = 258
+	tiffTagCompression               = 259
+	tiffTagPhotometricInterpretation = 262
+	tiffTagStripOffsets              = 273
+	tiffTagSamplesPerPixel           = 277
+	tiffTagRowsPerStrip              = 278
+	tiffTagStripByteCounts           = 279
+	tiffTagPlanarConfiguration       = 284
+	tiffTagTileWidth                 = 322
+	tiffTagSampleFormat              = 339
+	tiffTagModelPixelScale           = 33550
+	tiffTagModelTiepoint             = 33922
+	tiffTagGeoKeyDirectory           = 34735
+	tiffTagGDALNoData                = 42113
+)
+
+// TIFF のフィールドの型
+const (
+	tiffTypeASCII  = 2
+	tiffTypeShort  = 3
+	tiffTypeLong   = 4
+	tiffTypeDouble = 12
+)
+
+// GeoKey
+const (
+	geoKeyModelType          = 1024
+	geoKeyRasterType         = 1025
+	geoKeyGeographicType     = 2048
+	geoKeyGeogAngularUnits   = 2054
+	geoModelTypeGeographic   = 2
+	geoRasterPixelIsArea     = 1
+	geoAngularUnitDegree     = 9102
+	geoTIFFSampleFormatFloat = 3
+	// JGD2011 の地理座標系
+	epsgJGD2011 = 6668
+)
+
+type tiffEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+// WriteGeoTIFF 同じレベルの地域メッシュごとの値を、1メッシュを1画素とする GeoTIFF(EPSG:6668)として書き出す。
+//
+// 画素の大きさはレベルの緯度経度の間隔と等しく、値は float32 で書き出すため精度が落ちる。
+// 値のないメッシュの画素は nodata とし、nodata は GDAL_NODATA タグに記録する。
+// values に異なるレベルのメッシュが含まれる場合は ErrInvalidLevel、values が空の場合は ErrEmptyGeoTIFF、
+// メッシュの範囲の画素数が maxGeoTIFFPixels を超える場合は ErrGeoTIFFTooLarge を返す。
+func WriteGeoTIFF(w io.Writer, values map[MeshCode]float64, nodata float64) error {
+	if len(values) == 0 {
+		return ErrEmptyGeoTIFF
+	}
+	cells := make(map[cell]float64, len(values))
+	var level Level
+	minY, minX, maxY, maxX := math.MaxInt, math.MaxInt, math.MinInt, math.MinInt
+	for code, v := range values {
+		c, err := toCell(code)
+		if err != nil {
+			return err
+		}
+		if level != "" && c.level != level {
+			return ErrInvalidLevel
+		}
+		level = c.level
+		cells[c] = v
+		minY, minX = minInt(minY, c.y), minInt(minX, c.x)
+		maxY, maxX = maxInt(maxY, c.y), maxInt(maxX, c.x)
+	}
+	mesh, _ := getMesh(level)
+	width, height := maxX-minX+1, maxY-minY+1
+	if width > maxGeoTIFFPixels/height {
+		return ErrGeoTIFFTooLarge
+	}
+
+	// 北の行から順に、各行は西から東の順に並べる
+	image := make([]byte, width*height*4)
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			v, ok := cells[cell{level: level, y: maxY - row, x: minX + col}]
+			if !ok {
+				v = nodata
+			}
+			binary.LittleEndian.PutUint32(image[(row*width+col)*4:], math.Float32bits(float32(v)))
+		}
+	}
+
+	geoKeys := []uint16{
+		1, 1, 0, 4,
+		geoKeyModelType, 0, 1, geoModelTypeGeographic,
+		geoKeyRasterType, 0, 1, geoRasterPixelIsArea,
+		geoKeyGeographicType, 0, 1, epsgJGD2011,
+		geoKeyGeogAngularUnits, 0, 1, geoAngularUnitDegree,
+	}
+	entries := []tiffEntry{
+		tiffLong(tiffTagImageWidth, uint32(width)),
+		tiffLong(tiffTagImageLength, uint32(height)),
+		tiffShort(tiffTagBitsPerSample, 32),
+		tiffShort(tiffTagCompression, 1),
+		tiffShort(tiffTagPhotometricInterpretation, 1),
+		// 画像の位置はヘッダの直後
+		tiffLong(tiffTagStripOffsets, 8),
+		tiffShort(tiffTagSamplesPerPixel, 1),
+		tiffLong(tiffTagRowsPerStrip, uint32(height)),
+		tiffLong(tiffTagStripByteCounts, uint32(len(image))),
+		tiffShort(tiffTagPlanarConfiguration, 1),
+		tiffShort(tiffTagSampleFormat, geoTIFFSampleFormatFloat),
+		tiffDouble(tiffTagModelPixelScale, mesh.Distance.Lng, mesh.Distance.Lat, 0),
+		tiffDouble(tiffTagModelTiepoint, 0, 0, 0, 100+float64(minX)*mesh.Distance.Lng, float64(maxY+1)*mesh.Distance.Lat, 0),
+		tiffShort(tiffTagGeoKeyDirectory, geoKeys...),
+		tiffASCII(tiffTagGDALNoData, strconv.FormatFloat(nodata, 'g', -1, 32)),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("II")
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(42))
+	ifdOffset := 8 + len(image)
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(ifdOffset))
+	buf.Write(image)
+
+	// 4バイトに収まらない値は IFD の後に置く
+	extraOffset := ifdOffset + 2 + len(entries)*12 + 4
+	var extra bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(len(entries)))
+	for _, e := range entries {
+		_ = binary.Write(&buf, binary.LittleEndian, e.tag)
+		_ = binary.Write(&buf, binary.LittleEndian, e.typ)
+		_ = binary.Write(&buf, binary.LittleEndian, e.count)
+		if len(e.data) <= 4 {
+			var value [4]byte
+			copy(value[:], e.data)
+			buf.Write(value[:])
+			continue
+		}
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(extraOffset+extra.Len()))
+		extra.Write(e.data)
+		if extra.Len()%2 == 1 {
+			extra.WriteByte(0)
+		}
+	}
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(0))
+	buf.Write(extra.Bytes())
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadGeoTIFF WriteGeoTIFF で書き出した形式の GeoTIFF を読み込み、地域メッシュごとの値に変換する。
+//
+// 画素の大きさがいずれかのレベルの緯度経度の間隔と等しく、画素の境界がメッシュの境界と一致する
+// 非圧縮の float32 または float64 の GeoTIFF(EPSG:6668)に対応する。
+// nodata または NaN の画素と、日本の国土にかからない第1次地域区画の画素は読み飛ばす。
+func ReadGeoTIFF(r io.Reader) (map[MeshCode]float64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	t, err := parseTIFF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := t.uintValue(tiffTagImageWidth, 0), t.uintValue(tiffTagImageLength, 0)
+	bits := t.uintValue(tiffTagBitsPerSample, 1)
+	if width == 0 || height == 0 || t.has(tiffTagTileWidth) ||
+		t.uintValue(tiffTagCompression, 1) != 1 || t.uintValue(tiffTagSamplesPerPixel, 1) != 1 ||
+		t.uintValue(tiffTagSampleFormat, 1) != geoTIFFSampleFormatFloat || (bits != 32 && bits != 64) {
+		return nil, ErrInvalidGeoTIFF
+	}
+	if code, ok := t.geoKey(geoKeyGeographicType); !ok || code != epsgJGD2011 {
+		return nil, ErrInvalidGeoTIFF
+	}
+	scale, tiepoint := t.doubles(tiffTagModelPixelScale), t.doubles(tiffTagModelTiepoint)
+	if len(scale) < 2 || len(tiepoint) < 6 {
+		return nil, ErrInvalidGeoTIFF
+	}
+
+	// 画素の大きさからレベルを、左上の画素の位置から格子上の位置を求める
+	var level Level
+	var mesh Mesh
+	for _, l := range meshIDLevels[1:] {
+		m, _ := getMesh(l)
+		if nearlyEqual(scale[0], m.Distance.Lng) && nearlyEqual(scale[1], m.Distance.Lat) {
+			level, mesh = l, m
+			break
+		}
+	}
+	if level == "" {
+		return nil, ErrInvalidGeoTIFF
+	}
+	originX := (tiepoint[3]-100)/mesh.Distance.Lng - tiepoint[0]
+	top := tiepoint[4]/mesh.Distance.Lat + tiepoint[1]
+	minX, maxY := int(math.Round(originX)), int(math.Round(top))-1
+	if !nearlyEqual(originX, float64(minX)) || !nearlyEqual(top, float64(maxY+1)) {
+		return nil, ErrInvalidGeoTIFF
+	}
+
+	// ストリップを順に連結した画像
+	offsets, counts := t.uints(tiffTagStripOffsets), t.uints(tiffTagStripByteCounts)
+	if len(offsets) == 0 || len(offsets) != len(counts) {
+		return nil, ErrInvalidGeoTIFF
+	}
+	// 画像はファイルに収まるため、画素数がファイルの大きさを超える場合は不正な値として扱う
+	if height > len(data)/width {
+		return nil, ErrInvalidGeoTIFF
+	}
+	size := width * height * bits / 8
+	if size > len(data) {
+		return nil, ErrInvalidGeoTIFF
+	}
+	image := make([]byte, 0, size)
+	for i, offset := range offsets {
+		if offset > len(data) || counts[i] > len(data)-offset {
+			return nil, ErrInvalidGeoTIFF
+		}
+		// 画像の大きさを超える部分は使用しない
+		n := minInt(counts[i], size-len(image))
+		image = append(image, data[offset:offset+n]...)
+	}
+	if len(image) < size {
+		return nil, ErrInvalidGeoTIFF
+	}
+
+	nodata := math.NaN()
+	if s, ok := t.ascii(tiffTagGDALNoData); ok {
+		if nodata, err = strconv.ParseFloat(strings.TrimSpace(s), 64); err != nil {
+			return nil, ErrInvalidGeoTIFF
+		}
+		if bits == 32 {
+			nodata = float64(float32(nodata))
+		}
+	}
+
+	values := make(map[MeshCode]float64)
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			i := row*width + col
+			var v float64
+			if bits == 32 {
+				v = float64(math.Float32frombits(t.order.Uint32(image[i*4:])))
+			} else {
+				v = math.Float64frombits(t.order.Uint64(image[i*8:]))
+			}
+			if math.IsNaN(v) || v == nodata {
+				continue
+			}
+			code, err := cell{level: level, y: maxY - row, x: minX + col}.toCode()
+			if err != nil {
+				continue
+			}
+			values[code] = v
+		}
+	}
+	return values, nil
+}
+
+func nearlyEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func tiffShort(tag uint16, values ...uint16) tiffEntry {
+	data := make([]byte, len(values)*2)
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(data[i*2:], v)
+	}
+	return tiffEntry{tag: tag, typ: tiffTypeShort, count: uint32(len(values)), data: data}
+}
+
+func tiffLong(tag uint16, values ...uint32) tiffEntry {
+	data := make([]byte, len(values)*4)
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[i*4:], v)
+	}
+	return tiffEntry{tag: tag, typ: tiffTypeLong, count: uint32(len(values)), data: data}
+}
+
+func tiffDouble(tag uint16, values ...float64) tiffEntry {
+	data := make([]byte, len(values)*8)
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(data[i*8:], math.Float64bits(v))
+	}
+	return tiffEntry{tag: tag, typ: tiffTypeDouble, count: uint32(len(values)), data: data}
+}
+
+func tiffASCII(tag uint16, s string) tiffEntry {
+	data := append([]byte(s), 0)
+	return tiffEntry{tag: tag, typ: tiffTypeASCII, count: uint32(len(data)), data: data}
+}
+
+// tiffFile 読み込んだ TIFF の最初の IFD
+type tiffFile struct {
+	order   binary.ByteOrder
+	entries map[uint16]tiffEntry
+}
+
+func parseTIFF(data []byte) (*tiffFile, error) {
+	if len(data) < 8 {
+		return nil, ErrInvalidGeoTIFF
+	}
+	t := &tiffFile{entries: make(map[uint16]tiffEntry)}
+	switch string(data[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return nil, ErrInvalidGeoTIFF
+	}
+	if t.order.Uint16(data[2:]) != 42 {
+		return nil, ErrInvalidGeoTIFF
+	}
+	offset := int(t.order.Uint32(data[4:]))
+	if offset+2 > len(data) {
+		return nil, ErrInvalidGeoTIFF
+	}
+	n := int(t.order.Uint16(data[offset:]))
+	if offset+2+n*12 > len(data) {
+		return nil, ErrInvalidGeoTIFF
+	}
+	for i := 0; i < n; i++ {
+		b := data[offset+2+i*12:]
+		e := tiffEntry{tag: t.order.Uint16(b), typ: t.order.Uint16(b[2:]), count: t.order.Uint32(b[4:])}
+		var size int
+		switch e.typ {
+		case tiffTypeASCII:
+			size = 1
+		case tiffTypeShort:
+			size = 2
+		case tiffTypeLong:
+			size = 4
+		case tiffTypeDouble:
+			size = 8
+		default:
+			// 使用しない型のタグは読み飛ばす
+			continue
+		}
+		length := size * int(e.count)
+		if length <= 4 {
+			e.data = b[8 : 8+length]
+		} else {
+			start := int(t.order.Uint32(b[8:]))
+			if start < 0 || start+length > len(data) {
+				return nil, ErrInvalidGeoTIFF
+			}
+			e.data = data[start : start+length]
+		}
+		t.entries[e.tag] = e
+	}
+	return t, nil
+}
+
+func (t *tiffFile) has(tag uint16) bool {
+	_, ok := t.entries[tag]
+	return ok
+}
+
+func (t *tiffFile) uints(tag uint16) []int {
+	e, ok := t.entries[tag]
+	if !ok {
+		return nil
+	}
+	values := make([]int, e.count)
+	for i := range values {
+		switch e.typ {
+		case tiffTypeShort:
+			values[i] = int(t.order.Uint16(e.data[i*2:]))
+		case tiffTypeLong:
+			values[i] = int(t.order.Uint32(e.data[i*4:]))
+		default:
+			return nil
+		}
+	}
+	return values
+}
+
+// uintValue タグの最初の値を取得する。タグがない場合は def を返す。
+func (t *tiffFile) uintValue(tag uint16, def int) int {
+	if values := t.uints(tag); len(values) > 0 {
+		return values[0]
+	}
+	return def
+}
+
+func (t *tiffFile) doubles(tag uint16) []float64 {
+	e, ok := t.entries[tag]
+	if !ok || e.typ != tiffTypeDouble {
+		return nil
+	}
+	values := make([]float64, e.count)
+	for i := range values {
+		values[i] = math.Float64frombits(t.order.Uint64(e.data[i*8:]))
+	}
+	return values
+}
+
+func (t *tiffFile) ascii(tag uint16) (string, bool) {
+	e, ok := t.entries[tag]
+	if !ok || e.typ != tiffTypeASCII {
+		return "", false
+	}
+	return strings.TrimRight(string(e.data), "\x00"), true
+}
+
+// geoKey GeoKeyDirectory に直接記録された GeoKey の値を取得する。
+func (t *tiffFile) geoKey(key uint16) (int, bool) {
+	dir := t.uints(tiffTagGeoKeyDirectory)
+	if len(dir) < 4 {
+		return 0, false
+	}
+	keys := dir[4:]
+	i := sort.Search(len(keys)/4, func(i int) bool { return keys[i*4] >= int(key) })
+	if i*4+3 >= len(keys) || keys[i*4] != int(key) || keys[i*4+1] != 0 {
+		return 0, false
+	}
+	return keys[i*4+3], true
+}
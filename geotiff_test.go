@@ -0,0 +1,112 @@
+package japanmesh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGeoTIFF_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[MeshCode]float64
+	}{
+		{name: "level3", values: map[MeshCode]float64{"53394547": 12, "53394548": 0.5, "53394557": -3}},
+		// 第1次地域区画の境界をまたぐ
+		{name: "across level1", values: map[MeshCode]float64{"53390000": 1, "53380709": 2, "52397090": 3}},
+		{name: "level1", values: map[MeshCode]float64{"5339": 1, "5440": 2}},
+		{name: "one eighth", values: map[MeshCode]float64{"53394547111": 1, "53394547444": 2}},
+		{name: "twofold", values: map[MeshCode]float64{"533945005": 1, "533945285": 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteGeoTIFF(&buf, tt.values, -9999); err != nil {
+				t.Fatalf("WriteGeoTIFF() error = %v", err)
+			}
+			got, err := ReadGeoTIFF(&buf)
+			if err != nil {
+				t.Fatalf("ReadGeoTIFF() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.values) {
+				t.Errorf("ReadGeoTIFF() got = %v, want %v", got, tt.values)
+			}
+		})
+	}
+}
+
+func TestWriteGeoTIFF_Tags(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGeoTIFF(&buf, map[MeshCode]float64{"53394547": 1, "53394559": 2}, math.NaN()); err != nil {
+		t.Fatalf("WriteGeoTIFF() error = %v", err)
+	}
+	tiff, err := parseTIFF(buf.Bytes())
+	if err != nil {
+		t.Fatalf("parseTIFF() error = %v", err)
+	}
+	if w, h := tiff.uintValue(tiffTagImageWidth, 0), tiff.uintValue(tiffTagImageLength, 0); w != 3 || h != 2 {
+		t.Errorf("WriteGeoTIFF() size = %vx%v, want 3x2", w, h)
+	}
+	if got := tiff.doubles(tiffTagModelPixelScale); !reflect.DeepEqual(got, []float64{level3Mesh.Distance.Lng, level3Mesh.Distance.Lat, 0}) {
+		t.Errorf("WriteGeoTIFF() pixel scale = %v", got)
+	}
+	bounds, _ := ToBounds("53394557")
+	if got := tiff.doubles(tiffTagModelTiepoint); math.Abs(got[3]-bounds.Min.Longitude) > 1e-9 || math.Abs(got[4]-bounds.Max.Latitude) > 1e-9 {
+		t.Errorf("WriteGeoTIFF() tiepoint = %v, want %v", got, bounds)
+	}
+	if got, ok := tiff.geoKey(geoKeyGeographicType); !ok || got != epsgJGD2011 {
+		t.Errorf("WriteGeoTIFF() GeographicTypeGeoKey = %v", got)
+	}
+	if got, _ := tiff.ascii(tiffTagGDALNoData); got != "NaN" {
+		t.Errorf("WriteGeoTIFF() nodata = %v, want NaN", got)
+	}
+}
+
+func TestGeoTIFF_Error(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGeoTIFF(&buf, map[MeshCode]float64{"53394547": 1, "533945": 2}, 0); !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("WriteGeoTIFF() error = %v, wantErr %v", err, ErrInvalidLevel)
+	}
+	if err := WriteGeoTIFF(&buf, nil, 0); !errors.Is(err, ErrEmptyGeoTIFF) {
+		t.Errorf("WriteGeoTIFF() error = %v, wantErr %v", err, ErrEmptyGeoTIFF)
+	}
+	// 与那国島、沖ノ鳥島、札幌の1/8地域メッシュを含む範囲は画素数が上限を超える
+	if err := WriteGeoTIFF(&buf, map[MeshCode]float64{"36224711111": 1, "30364711111": 2, "68414711111": 3}, 0); !errors.Is(err, ErrGeoTIFFTooLarge) {
+		t.Errorf("WriteGeoTIFF() error = %v, wantErr %v", err, ErrGeoTIFFTooLarge)
+	}
+
+	buf.Reset()
+	_ = WriteGeoTIFF(&buf, map[MeshCode]float64{"53394547": 1}, 0)
+	valid := buf.Bytes()
+	// 画像の幅と高さを書き換えたもの
+	resized := func(width, height uint32) []byte {
+		data := append([]byte(nil), valid...)
+		tf, err := parseTIFF(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		binary.LittleEndian.PutUint32(tf.entries[tiffTagImageWidth].data, width)
+		binary.LittleEndian.PutUint32(tf.entries[tiffTagImageLength].data, height)
+		return data
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "not tiff", data: []byte("PK\x03\x04abcdefgh")},
+		{name: "truncated", data: valid[:len(valid)-40]},
+		{name: "overflow size", data: resized(math.MaxUint32, math.MaxUint32)},
+		{name: "larger than file", data: resized(64, 64)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadGeoTIFF(bytes.NewReader(tt.data)); !errors.Is(err, ErrInvalidGeoTIFF) {
+				t.Errorf("ReadGeoTIFF() error = %v, wantErr %v", err, ErrInvalidGeoTIFF)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Package grpcserver は japanmeshpb.MeshServiceServer を実装し、地域メッシュコードの変換・検索を gRPC で提供する。
+//
+//	s := grpc.NewServer()
+//	japanmeshpb.RegisterMeshServiceServer(s, grpcserver.NewServer())
+//
+// 不正なメッシュコード・緯度経度・レベル・図形は codes.InvalidArgument、
+// 日本の国土にかからない緯度経度は codes.OutOfRange、
+// Cover で求めるメッシュが多すぎる図形とレベルの組み合わせは codes.ResourceExhausted とする。
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+	"github.com/keitaro1020/go-japanmesh/japanmeshpb"
+	geojson "github.com/paulmach/go.geojson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// coverModes CoverMode に対応する判定方法
+var coverModes = map[japanmeshpb.CoverMode]japanmesh.CoverMode{
+	japanmeshpb.CoverMode_COVER_MODE_UNSPECIFIED: japanmesh.CoverIntersects,
+	japanmeshpb.CoverMode_COVER_MODE_INTERSECTS:  japanmesh.CoverIntersects,
+	japanmeshpb.CoverMode_COVER_MODE_CONTAINS:    japanmesh.CoverContains,
+	japanmeshpb.CoverMode_COVER_MODE_CENTER:      japanmesh.CoverCenter,
+}
+
+// defaultMaxCoverCells Cover で求められるメッシュの数の既定値
+const defaultMaxCoverCells = 100000
+
+// Server japanmeshpb.MeshServiceServer の実装
+type Server struct {
+	japanmeshpb.UnimplementedMeshServiceServer
+	maxCoverCells int
+}
+
+// Option Server の設定
+type Option func(*Server)
+
+// WithMaxCoverCells Cover で求められるメッシュの数の上限を設定する。既定値は 100000。
+// 図形の範囲にかかるメッシュの数が上限を超える場合は、メッシュを求めずに codes.ResourceExhausted を返す。
+func WithMaxCoverCells(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.maxCoverCells = n
+		}
+	}
+}
+
+// NewServer Server を生成する。
+func NewServer(opts ...Option) *Server {
+	s := &Server{maxCoverCells: defaultMaxCoverCells}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Encode 緯度経度から地域メッシュコードを求める。
+func (s *Server) Encode(_ context.Context, req *japanmeshpb.EncodeRequest) (*japanmeshpb.EncodeResponse, error) {
+	resp, err := encode(req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return resp, nil
+}
+
+// EncodeStream 受け取った緯度経度を順に地域メッシュコードへ変換する。
+// 変換できない緯度経度は Error を設定して返し、ストリームを続ける。
+func (s *Server) EncodeStream(stream japanmeshpb.MeshService_EncodeStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		resp, err := encode(req)
+		if err != nil {
+			resp = &japanmeshpb.EncodeResponse{Level: req.GetLevel(), Error: err.Error()}
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// Decode 地域メッシュコードの範囲と中心を求める。
+func (s *Server) Decode(_ context.Context, req *japanmeshpb.DecodeRequest) (*japanmeshpb.DecodeResponse, error) {
+	code, err := parseCode(req.GetCode())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	level, _ := japanmesh.GetLevel(code)
+	bounds, err := japanmesh.ToBounds(code)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &japanmeshpb.DecodeResponse{
+		Code:   string(code),
+		Level:  string(level),
+		Bounds: &japanmeshpb.Bounds{Min: toLatLng(bounds.Min), Max: toLatLng(bounds.Max)},
+		Center: toLatLng(bounds.Center()),
+	}, nil
+}
+
+// Children 1つ下のレベルの地域メッシュコードを求める。
+func (s *Server) Children(_ context.Context, req *japanmeshpb.ChildrenRequest) (*japanmeshpb.ChildrenResponse, error) {
+	code, err := parseCode(req.GetCode())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	children, err := japanmesh.GetCodes(code)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &japanmeshpb.ChildrenResponse{Codes: toStrings(children)}, nil
+}
+
+// Neighbors 隣接する同じレベルの地域メッシュコードを北から時計回りに求める。
+func (s *Server) Neighbors(_ context.Context, req *japanmeshpb.NeighborsRequest) (*japanmeshpb.NeighborsResponse, error) {
+	code, err := parseCode(req.GetCode())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	neighbors, err := japanmesh.GetNeighbors(code)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &japanmeshpb.NeighborsResponse{Codes: toStrings(neighbors)}, nil
+}
+
+// Cover GeoJSON の図形を覆う地域メッシュコードを求める。
+func (s *Server) Cover(_ context.Context, req *japanmeshpb.CoverRequest) (*japanmeshpb.CoverResponse, error) {
+	level, err := parseLevel(req.GetLevel())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	mode, ok := coverModes[req.GetMode()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown cover mode: %v", req.GetMode())
+	}
+	g, err := geojson.UnmarshalGeometry([]byte(req.GetGeojson()))
+	if err != nil {
+		return nil, statusError(fmt.Errorf("%w: %v", japanmesh.ErrInvalidGeometry, err))
+	}
+	n, err := estimateCoverCells(g, level)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if n > float64(s.maxCoverCells) {
+		return nil, status.Errorf(codes.ResourceExhausted, "too many meshes: about %.0f (max %d)", n, s.maxCoverCells)
+	}
+	covered, err := japanmesh.Cover(g, level, mode)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &japanmeshpb.CoverResponse{Codes: toStrings(covered)}, nil
+}
+
+// estimateCoverCells 図形を覆うメッシュの数の上限を見積もる。
+// 点は1点につき1つ、多角形は多角形ごとの範囲にかかるメッシュの数とし、合計する。
+// Cover が対応しない図形は ErrInvalidGeometry を返す。
+func estimateCoverCells(g *geojson.Geometry, level japanmesh.Level) (float64, error) {
+	mesh, err := japanmesh.GetMesh(level)
+	if err != nil {
+		return 0, err
+	}
+	var polygons [][][][]float64
+	switch g.Type {
+	case geojson.GeometryPoint:
+		return 1, nil
+	case geojson.GeometryMultiPoint:
+		return float64(len(g.MultiPoint)), nil
+	case geojson.GeometryPolygon:
+		polygons = [][][][]float64{g.Polygon}
+	case geojson.GeometryMultiPolygon:
+		polygons = g.MultiPolygon
+	default:
+		return 0, fmt.Errorf("%w: unsupported type %q", japanmesh.ErrInvalidGeometry, g.Type)
+	}
+	var total float64
+	for _, polygon := range polygons {
+		total += estimatePolygonCells(polygon, mesh)
+	}
+	return total, nil
+}
+
+// estimatePolygonCells 多角形の範囲にかかるメッシュの数を求める。
+func estimatePolygonCells(polygon [][][]float64, mesh japanmesh.Mesh) float64 {
+	minLng, minLat, maxLng, maxLat := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, ring := range polygon {
+		for _, p := range ring {
+			if len(p) < 2 {
+				continue
+			}
+			minLng, maxLng = math.Min(minLng, p[0]), math.Max(maxLng, p[0])
+			minLat, maxLat = math.Min(minLat, p[1]), math.Max(maxLat, p[1])
+		}
+	}
+	if minLng > maxLng || minLat > maxLat {
+		return 0
+	}
+	// 地域メッシュを定義できる範囲(北緯0〜66度40分、東経100〜180度)に限る
+	clamp := func(v, min, max float64) float64 { return math.Max(min, math.Min(max, v)) }
+	minLat, maxLat = clamp(minLat, 0, float64(200)/3), clamp(maxLat, 0, float64(200)/3)
+	minLng, maxLng = clamp(minLng, 100, 180), clamp(maxLng, 100, 180)
+	rows := math.Floor(maxLat/mesh.Distance.Lat) - math.Floor(minLat/mesh.Distance.Lat) + 1
+	cols := math.Floor((maxLng-100)/mesh.Distance.Lng) - math.Floor((minLng-100)/mesh.Distance.Lng) + 1
+	return rows * cols
+}
+
+func encode(req *japanmeshpb.EncodeRequest) (*japanmeshpb.EncodeResponse, error) {
+	level, err := parseLevel(req.GetLevel())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetPoint() == nil {
+		return nil, fmt.Errorf("%w: point is required", japanmesh.ErrInvalidGeoCode)
+	}
+	code, err := japanmesh.ToCode(japanmesh.GeoCode{Latitude: req.Point.GetLatitude(), Longitude: req.Point.GetLongitude()}, level)
+	if err != nil {
+		return nil, err
+	}
+	return &japanmeshpb.EncodeResponse{Code: string(code), Level: string(level)}, nil
+}
+
+// parseCode 地域メッシュコードを検証する。
+func parseCode(s string) (japanmesh.MeshCode, error) {
+	code := japanmesh.MeshCode(s)
+	if err := japanmesh.Validate(code); err != nil {
+		return "", fmt.Errorf("%w: %q", err, code)
+	}
+	return code, nil
+}
+
+// parseLevel レベルを検証する。空の場合は第3次地域区画とする。
+func parseLevel(s string) (japanmesh.Level, error) {
+	if s == "" {
+		return japanmesh.Level3, nil
+	}
+	return japanmesh.ParseLevel(s)
+}
+
+// statusError エラーを gRPC のステータスに変換する。
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, japanmesh.ErrInvalidArea):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, japanmesh.ErrInvalidMeshCode),
+		errors.Is(err, japanmesh.ErrInvalidGeoCode),
+		errors.Is(err, japanmesh.ErrInvalidLevel),
+		errors.Is(err, japanmesh.ErrInvalidGeometry):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func toLatLng(g japanmesh.GeoCode) *japanmeshpb.LatLng {
+	return &japanmeshpb.LatLng{Latitude: g.Latitude, Longitude: g.Longitude}
+}
+
+func toStrings(meshCodes japanmesh.MeshCodes) []string {
+	s := make([]string, len(meshCodes))
+	for i, code := range meshCodes {
+		s[i] = string(code)
+	}
+	return s
+}
@@ -0,0 +1,232 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/keitaro1020/go-japanmesh/japanmeshpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newClient ネットワークを使わずにサーバーと接続したクライアントを生成する。
+func newClient(t *testing.T, opts ...Option) japanmeshpb.MeshServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	japanmeshpb.RegisterMeshServiceServer(s, NewServer(opts...))
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return japanmeshpb.NewMeshServiceClient(conn)
+}
+
+func TestServer_Encode(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *japanmeshpb.EncodeRequest
+		want     string
+		wantCode codes.Code
+	}{
+		{name: "default level", req: &japanmeshpb.EncodeRequest{Point: &japanmeshpb.LatLng{Latitude: 35.70078, Longitude: 139.71475}}, want: "53394547"},
+		{name: "half", req: &japanmeshpb.EncodeRequest{Point: &japanmeshpb.LatLng{Latitude: 35.70078, Longitude: 139.71475}, Level: "1/2"}, want: "533945471"},
+		{name: "invalid area", req: &japanmeshpb.EncodeRequest{Point: &japanmeshpb.LatLng{Latitude: 10, Longitude: 100}}, wantCode: codes.OutOfRange},
+		{name: "invalid level", req: &japanmeshpb.EncodeRequest{Point: &japanmeshpb.LatLng{Latitude: 35.7, Longitude: 139.7}, Level: "4"}, wantCode: codes.InvalidArgument},
+		{name: "missing point", req: &japanmeshpb.EncodeRequest{}, wantCode: codes.InvalidArgument},
+	}
+	client := newClient(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Encode(context.Background(), tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Encode() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && resp.GetCode() != tt.want {
+				t.Errorf("Encode() = %v, want %v", resp.GetCode(), tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_EncodeStream(t *testing.T) {
+	client := newClient(t)
+	stream, err := client.EncodeStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := []*japanmeshpb.EncodeRequest{
+		{Point: &japanmeshpb.LatLng{Latitude: 35.70078, Longitude: 139.71475}},
+		{Point: &japanmeshpb.LatLng{Latitude: 10, Longitude: 100}},
+		{Point: &japanmeshpb.LatLng{Latitude: 35.70078, Longitude: 139.71475}, Level: "2"},
+	}
+	for _, req := range reqs {
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	var got [][2]string
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, [2]string{resp.GetCode(), resp.GetError()})
+	}
+	want := [][2]string{{"53394547", ""}, {"", "invalid area"}, {"533945", ""}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeStream() = %v, want %v", got, want)
+	}
+}
+
+func TestServer_Decode(t *testing.T) {
+	client := newClient(t)
+	resp, err := client.Decode(context.Background(), &japanmeshpb.DecodeRequest{Code: "533945"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetLevel() != "2" || resp.GetBounds().GetMin().GetLongitude() != 139.625 || resp.GetBounds().GetMax().GetLatitude() != 35.75 ||
+		resp.GetCenter().GetLongitude() != 139.6875 {
+		t.Errorf("Decode() = %v", resp)
+	}
+	if _, err := client.Decode(context.Background(), &japanmeshpb.DecodeRequest{Code: "53394"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Decode() error = %v, want %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestServer_Codes(t *testing.T) {
+	client := newClient(t)
+	tests := []struct {
+		name     string
+		call     func() ([]string, error)
+		want     []string
+		wantCode codes.Code
+	}{
+		{
+			name: "children",
+			call: func() ([]string, error) {
+				resp, err := client.Children(context.Background(), &japanmeshpb.ChildrenRequest{Code: "53394547"})
+				return resp.GetCodes(), err
+			},
+			want: []string{"533945471", "533945472", "533945473", "533945474"},
+		},
+		{
+			name: "children of one eighth",
+			call: func() ([]string, error) {
+				resp, err := client.Children(context.Background(), &japanmeshpb.ChildrenRequest{Code: "53394547111"})
+				return resp.GetCodes(), err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "neighbors",
+			call: func() ([]string, error) {
+				resp, err := client.Neighbors(context.Background(), &japanmeshpb.NeighborsRequest{Code: "533945"})
+				return resp.GetCodes(), err
+			},
+			want: []string{"533955", "533956", "533946", "533936", "533935", "533934", "533944", "533954"},
+		},
+		{
+			name: "cover",
+			call: func() ([]string, error) {
+				resp, err := client.Cover(context.Background(), &japanmeshpb.CoverRequest{
+					Geojson: `{"type":"Polygon","coordinates":[[[139.7125,35.7],[139.725,35.7],[139.725,35.70833333333333],[139.7125,35.70833333333333],[139.7125,35.7]]]}`,
+					Level:   "1/2",
+					Mode:    japanmeshpb.CoverMode_COVER_MODE_CONTAINS,
+				})
+				return resp.GetCodes(), err
+			},
+			want: []string{"533945471", "533945472", "533945473", "533945474"},
+		},
+		{
+			name: "cover point",
+			call: func() ([]string, error) {
+				resp, err := client.Cover(context.Background(), &japanmeshpb.CoverRequest{Geojson: `{"type":"Point","coordinates":[139.71475,35.70078]}`})
+				return resp.GetCodes(), err
+			},
+			want: []string{"53394547"},
+		},
+		{
+			name: "cover invalid geojson",
+			call: func() ([]string, error) {
+				resp, err := client.Cover(context.Background(), &japanmeshpb.CoverRequest{Geojson: `{"type":"LineString","coordinates":[[139,35],[140,36]]}`})
+				return resp.GetCodes(), err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "cover unknown mode",
+			call: func() ([]string, error) {
+				resp, err := client.Cover(context.Background(), &japanmeshpb.CoverRequest{Geojson: `{"type":"Point","coordinates":[139.71475,35.70078]}`, Mode: 9})
+				return resp.GetCodes(), err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call()
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("codes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_CoverLimit(t *testing.T) {
+	// 53394547 の範囲
+	square := `{"type":"Polygon","coordinates":[[[139.7125,35.7],[139.725,35.7],[139.725,35.70833333333333],[139.7125,35.70833333333333],[139.7125,35.7]]]}`
+	// 利尻島と与那国島の近くの小さな多角形。全体の範囲は広いが、覆うメッシュは少ない
+	sparse := `{"type":"MultiPolygon","coordinates":[` +
+		`[[[141.2,45.18],[141.21,45.18],[141.21,45.19],[141.2,45.19],[141.2,45.18]]],` +
+		`[[[122.98,24.45],[122.99,24.45],[122.99,24.46],[122.98,24.46],[122.98,24.45]]]]}`
+	// 本州を覆う範囲
+	honshu := `{"type":"Polygon","coordinates":[[[130,33],[142,33],[142,42],[130,42],[130,33]]]}`
+	tests := []struct {
+		name     string
+		opts     []Option
+		req      *japanmeshpb.CoverRequest
+		wantCode codes.Code
+	}{
+		{name: "default limit", req: &japanmeshpb.CoverRequest{Geojson: honshu, Level: "1/8"}, wantCode: codes.ResourceExhausted},
+		{name: "within default limit", req: &japanmeshpb.CoverRequest{Geojson: honshu, Level: "2"}, wantCode: codes.OK},
+		{name: "sparse multipolygon", req: &japanmeshpb.CoverRequest{Geojson: sparse, Level: "1/8"}, wantCode: codes.OK},
+		{name: "line string", req: &japanmeshpb.CoverRequest{Geojson: `{"type":"LineString","coordinates":[[130,33],[142,42]]}`, Level: "1/8"}, wantCode: codes.InvalidArgument},
+		{name: "geometry collection", req: &japanmeshpb.CoverRequest{Geojson: `{"type":"GeometryCollection","geometries":[]}`, Level: "1/8"}, wantCode: codes.InvalidArgument},
+		{name: "custom limit", opts: []Option{WithMaxCoverCells(3)}, req: &japanmeshpb.CoverRequest{Geojson: square, Level: "1/2"}, wantCode: codes.ResourceExhausted},
+		{name: "within custom limit", opts: []Option{WithMaxCoverCells(4)}, req: &japanmeshpb.CoverRequest{Geojson: square, Level: "1/2", Mode: japanmeshpb.CoverMode_COVER_MODE_CONTAINS}, wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClient(t, tt.opts...)
+			if _, err := client.Cover(context.Background(), tt.req); status.Code(err) != tt.wantCode {
+				t.Errorf("Cover() error = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
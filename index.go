@@ -0,0 +1,260 @@
+package japanmesh
+
+import (
+	"math"
+	"sort"
+)
+
+// MeshIndex 異なるレベルが混在する地域メッシュコードの集合から、緯度経度を含むメッシュや近いメッシュを検索する索引。
+// 生成後は並行して使用できる。
+type MeshIndex struct {
+	codes map[MeshCode]struct{}
+	// 含まれる統合地域メッシュのレベル
+	integrated []Level
+	entries    []indexEntry
+	// 第2次地域区画の格子ごとに、重なるメッシュの entries 上の位置を保持する
+	buckets map[indexBucket][]int
+	// buckets の格子が含まれる範囲
+	minBucket, maxBucket indexBucket
+}
+
+// indexBucket 第2次地域区画の格子上の位置
+type indexBucket struct {
+	y, x int
+}
+
+type indexEntry struct {
+	code   MeshCode
+	bounds Bounds
+}
+
+// NearestMesh MeshIndex.Nearest の検索結果
+type NearestMesh struct {
+	Code MeshCode
+	// 緯度経度からメッシュまでの測地線距離(m)。メッシュに含まれる場合は0
+	Distance float64
+}
+
+// nearestMargin 近似した距離で絞り込む際の誤差の許容範囲
+const (
+	nearestMarginRatio = 1.05
+	nearestMarginMeter = 10
+)
+
+// NewMeshIndex 地域メッシュコードの索引を生成する。重複するメッシュコードは1つにまとめる。
+func NewMeshIndex(codes MeshCodes) (*MeshIndex, error) {
+	idx := &MeshIndex{
+		codes:   make(map[MeshCode]struct{}, len(codes)),
+		buckets: make(map[indexBucket][]int),
+	}
+	for _, code := range codes {
+		if _, ok := idx.codes[code]; ok {
+			continue
+		}
+		c, err := toCell(code)
+		if err != nil {
+			return nil, err
+		}
+		if !isStandardLevel(c.level) && !containsLevel(idx.integrated, c.level) {
+			idx.integrated = append(idx.integrated, c.level)
+		}
+		idx.codes[code] = struct{}{}
+		idx.addBuckets(c, len(idx.entries))
+		idx.entries = append(idx.entries, indexEntry{code: code, bounds: c.bounds()})
+	}
+	return idx, nil
+}
+
+// addBuckets メッシュが重なる第2次地域区画の格子に entries 上の位置 i を登録する。
+func (idx *MeshIndex) addBuckets(c cell, i int) {
+	n, d := getCellCount(c.level), level2Mesh.Division.Y
+	minY, maxY := c.y*d/n, ((c.y+1)*d-1)/n
+	minX, maxX := c.x*d/n, ((c.x+1)*d-1)/n
+	if len(idx.entries) == 0 {
+		idx.minBucket, idx.maxBucket = indexBucket{y: minY, x: minX}, indexBucket{y: maxY, x: maxX}
+	}
+	idx.minBucket.y, idx.minBucket.x = minInt(idx.minBucket.y, minY), minInt(idx.minBucket.x, minX)
+	idx.maxBucket.y, idx.maxBucket.x = maxInt(idx.maxBucket.y, maxY), maxInt(idx.maxBucket.x, maxX)
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			b := indexBucket{y: y, x: x}
+			idx.buckets[b] = append(idx.buckets[b], i)
+		}
+	}
+}
+
+// Len 索引に含まれる地域メッシュコードの数を取得する。
+func (idx *MeshIndex) Len() int {
+	return len(idx.entries)
+}
+
+// Lookup 緯度経度を含む地域メッシュコードを、小さいメッシュから順に取得する。
+// 第1次地域区画から1/8地域メッシュまでの上位のメッシュコードをたどるため、索引の大きさによらず検索できる。
+func (idx *MeshIndex) Lookup(geoCode GeoCode) MeshCodes {
+	code, err := ToCode(geoCode, LevelOneEighth)
+	if err != nil {
+		return nil
+	}
+	var result MeshCodes
+	for _, level := range idx.integrated {
+		c, err := ToCode(geoCode, level)
+		if err != nil {
+			continue
+		}
+		if _, ok := idx.codes[c]; ok {
+			result = append(result, c)
+		}
+	}
+	ancestors := SplitCodeByLevel(code)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if _, ok := idx.codes[ancestors[i]]; ok {
+			result = append(result, ancestors[i])
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		li, _ := GetLevel(result[i])
+		lj, _ := GetLevel(result[j])
+		return getCellCount(li) > getCellCount(lj)
+	})
+	return result
+}
+
+// Contains 緯度経度を含む地域メッシュコードが索引にあるかを判定する。
+func (idx *MeshIndex) Contains(geoCode GeoCode) bool {
+	return len(idx.Lookup(geoCode)) > 0
+}
+
+// Nearest 緯度経度から近い順に k 個の地域メッシュコードを取得する。
+// 距離はメッシュの範囲内で最も近い点までの測地線距離とし、距離が等しい場合はメッシュコードの昇順とする。
+func (idx *MeshIndex) Nearest(geoCode GeoCode, k int) []NearestMesh {
+	if k <= 0 || len(idx.entries) == 0 {
+		return nil
+	}
+	if k > len(idx.entries) {
+		k = len(idx.entries)
+	}
+
+	// 緯度経度を含む第2次地域区画の格子から外側へ1周ずつ候補を集め、
+	// k 番目に近い候補までの距離が未探索の格子までの距離の下限より小さくなったら打ち切る
+	q := indexBucket{
+		y: int(math.Floor(geoCode.Latitude / level2Mesh.Distance.Lat)),
+		x: int(math.Floor((geoCode.Longitude - 100) / level2Mesh.Distance.Lng)),
+	}
+	seen := make(map[int]struct{})
+	var candidates []nearestCandidate
+	start := maxInt(0, maxInt(maxInt(idx.minBucket.y-q.y, q.y-idx.maxBucket.y), maxInt(idx.minBucket.x-q.x, q.x-idx.maxBucket.x)))
+	end := maxInt(maxInt(q.y-idx.minBucket.y, idx.maxBucket.y-q.y), maxInt(q.x-idx.minBucket.x, idx.maxBucket.x-q.x))
+	for r := start; r <= end; r++ {
+		idx.visitRing(q, r, func(i int) {
+			if _, ok := seen[i]; ok {
+				return
+			}
+			seen[i] = struct{}{}
+			// 平面に近似した距離で候補を絞り込んでから測地線距離を求める
+			candidates = append(candidates, nearestCandidate{
+				index:  i,
+				approx: approximateDistance(geoCode, idx.entries[i].bounds.Nearest(geoCode)),
+			})
+		})
+		if len(candidates) < k {
+			continue
+		}
+		sortCandidates(candidates)
+		if candidates[k-1].approx*nearestMarginRatio+nearestMarginMeter < idx.ringDistance(geoCode, q, r) {
+			break
+		}
+	}
+	sortCandidates(candidates)
+	threshold := candidates[k-1].approx*nearestMarginRatio + nearestMarginMeter
+
+	var result []NearestMesh
+	for _, c := range candidates {
+		if c.approx > threshold {
+			break
+		}
+		e := idx.entries[c.index]
+		result = append(result, NearestMesh{Code: e.code, Distance: e.bounds.DistanceTo(geoCode)})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Distance != result[j].Distance {
+			return result[i].Distance < result[j].Distance
+		}
+		return result[i].Code < result[j].Code
+	})
+	return result[:k]
+}
+
+// nearestCandidate Nearest の候補となる entries 上の位置と近似した距離
+type nearestCandidate struct {
+	index  int
+	approx float64
+}
+
+func sortCandidates(candidates []nearestCandidate) {
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].approx < candidates[j].approx })
+}
+
+// visitRing 格子 q からチェビシェフ距離が r の格子のうち、索引の範囲にあるものに登録された位置を fn に渡す。
+func (idx *MeshIndex) visitRing(q indexBucket, r int, fn func(i int)) {
+	visit := func(y, x int) {
+		for _, i := range idx.buckets[indexBucket{y: y, x: x}] {
+			fn(i)
+		}
+	}
+	minY, maxY := maxInt(q.y-r, idx.minBucket.y), minInt(q.y+r, idx.maxBucket.y)
+	minX, maxX := maxInt(q.x-r, idx.minBucket.x), minInt(q.x+r, idx.maxBucket.x)
+	for y := minY; y <= maxY; y++ {
+		if y == q.y-r || y == q.y+r {
+			for x := minX; x <= maxX; x++ {
+				visit(y, x)
+			}
+			continue
+		}
+		if q.x-r >= minX {
+			visit(y, q.x-r)
+		}
+		if r > 0 && q.x+r <= maxX {
+			visit(y, q.x+r)
+		}
+	}
+}
+
+// ringDistance 格子 q からチェビシェフ距離が r より大きい格子にあるメッシュまでの、近似した距離の下限(m)を求める。
+func (idx *MeshIndex) ringDistance(geoCode GeoCode, q indexBucket, r int) float64 {
+	south := float64(q.y-r) * level2Mesh.Distance.Lat
+	north := float64(q.y+r+1) * level2Mesh.Distance.Lat
+	west := 100 + float64(q.x-r)*level2Mesh.Distance.Lng
+	east := 100 + float64(q.x+r+1)*level2Mesh.Distance.Lng
+	dLat := math.Min(geoCode.Latitude-south, north-geoCode.Latitude)
+	dLng := math.Min(geoCode.Longitude-west, east-geoCode.Longitude)
+	// 経度方向の距離は高緯度ほど短くなるため、索引の範囲で最も高い緯度で求める
+	lat := math.Max(math.Abs(geoCode.Latitude), float64(idx.maxBucket.y+1)*level2Mesh.Distance.Lat)
+	return math.Min(
+		approximateDistance(geoCode, GeoCode{Latitude: geoCode.Latitude + dLat, Longitude: geoCode.Longitude}),
+		approximateDistance(GeoCode{Latitude: lat}, GeoCode{Latitude: lat, Longitude: dLng}),
+	)
+}
+
+// approximateDistance 2点の中間の緯度で平面に近似した距離(m)を求める。
+func approximateDistance(a, b GeoCode) float64 {
+	if a == b {
+		return 0
+	}
+	rad := math.Pi / 180
+	e2 := grs80Flattening * (2 - grs80Flattening)
+	sin, cos := math.Sincos((a.Latitude + b.Latitude) / 2 * rad)
+	w := math.Sqrt(1 - e2*sin*sin)
+	// 子午線曲率半径と卯酉線曲率半径
+	m := grs80SemiMajorAxis * (1 - e2) / (w * w * w)
+	n := grs80SemiMajorAxis / w
+	return math.Hypot((b.Latitude-a.Latitude)*rad*m, (b.Longitude-a.Longitude)*rad*n*cos)
+}
+
+func containsLevel(levels []Level, level Level) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package japanmesh
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMeshIndex_Lookup(t *testing.T) {
+	idx, err := NewMeshIndex(MeshCodes{"5339", "53394547", "533945471", "5339452", "533945", "6441", "53394547"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := idx.Len(); got != 6 {
+		t.Errorf("MeshIndex.Len() got = %v, want 6", got)
+	}
+	tests := []struct {
+		name     string
+		geoCode  GeoCode
+		want     MeshCodes
+		contains bool
+	}{
+		{
+			name:     "nested",
+			geoCode:  GeoCode{Latitude: 35.70078, Longitude: 139.71475},
+			want:     MeshCodes{"533945471", "53394547", "5339452", "533945", "5339"},
+			contains: true,
+		},
+		{
+			name:     "level1 only",
+			geoCode:  GeoCode{Latitude: 35.4, Longitude: 139.1},
+			want:     MeshCodes{"5339"},
+			contains: true,
+		},
+		{name: "not indexed", geoCode: GeoCode{Latitude: 34.9, Longitude: 135.5}, want: nil, contains: false},
+		{name: "out of area", geoCode: GeoCode{Latitude: 10, Longitude: 100}, want: nil, contains: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idx.Lookup(tt.geoCode); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MeshIndex.Lookup() got = %v, want %v", got, tt.want)
+			}
+			if got := idx.Contains(tt.geoCode); got != tt.contains {
+				t.Errorf("MeshIndex.Contains() got = %v, want %v", got, tt.contains)
+			}
+		})
+	}
+}
+
+func TestNewMeshIndex_Error(t *testing.T) {
+	if _, err := NewMeshIndex(MeshCodes{"5339", "53394"}); err == nil {
+		t.Error("NewMeshIndex() error = nil, want error")
+	}
+}
+
+func TestMeshIndex_Nearest(t *testing.T) {
+	idx, _ := NewMeshIndex(MeshCodes{"53394547", "53394548", "53394557", "53394537", "5235"})
+	geoCode := GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	got := idx.Nearest(geoCode, 3)
+	codes := make(MeshCodes, len(got))
+	for i, n := range got {
+		codes[i] = n.Code
+	}
+	if want := (MeshCodes{"53394547", "53394537", "53394557"}); !reflect.DeepEqual(codes, want) {
+		t.Errorf("MeshIndex.Nearest() got = %v, want %v", codes, want)
+	}
+	if got[0].Distance != 0 {
+		t.Errorf("MeshIndex.Nearest() containing distance = %v, want 0", got[0].Distance)
+	}
+	if got := idx.Nearest(geoCode, 10); len(got) != 5 {
+		t.Errorf("MeshIndex.Nearest() len = %v, want 5", len(got))
+	}
+	if got := idx.Nearest(geoCode, 0); got != nil {
+		t.Errorf("MeshIndex.Nearest() k=0 got = %v, want nil", got)
+	}
+}
+
+func TestMeshIndex_NearestBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var codes MeshCodes
+	for i := 0; i < 500; i++ {
+		code, err := ToCode(GeoCode{Latitude: 33 + r.Float64()*10, Longitude: 130 + r.Float64()*12}, LevelTwofold)
+		if err != nil {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	idx, err := NewMeshIndex(codes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		geoCode := GeoCode{Latitude: 33 + r.Float64()*10, Longitude: 130 + r.Float64()*12}
+		var want []float64
+		for _, e := range idx.entries {
+			want = append(want, e.bounds.DistanceTo(geoCode))
+		}
+		sort.Float64s(want)
+		got := idx.Nearest(geoCode, 5)
+		for j := range got {
+			if math.Abs(got[j].Distance-want[j]) > 1e-6 {
+				t.Fatalf("MeshIndex.Nearest() distance[%d] = %v, want %v", j, got[j].Distance, want[j])
+			}
+		}
+	}
+}
+
+func TestMeshIndex_NearestMixedLevels(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	levels := []Level{Level1, Level2, Level3, LevelOneEighth, LevelFivefold}
+	var codes MeshCodes
+	for i := 0; i < 300; i++ {
+		code, err := ToCode(GeoCode{Latitude: 30 + r.Float64()*15, Longitude: 128 + r.Float64()*15}, levels[i%len(levels)])
+		if err != nil {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	idx, err := NewMeshIndex(codes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 索引の範囲の外側の緯度経度も含めて検索する
+	for i := 0; i < 50; i++ {
+		geoCode := GeoCode{Latitude: 20 + r.Float64()*30, Longitude: 120 + r.Float64()*35}
+		var want []float64
+		for _, e := range idx.entries {
+			want = append(want, e.bounds.DistanceTo(geoCode))
+		}
+		sort.Float64s(want)
+		got := idx.Nearest(geoCode, 8)
+		if len(got) != 8 {
+			t.Fatalf("MeshIndex.Nearest() len = %v, want 8", len(got))
+		}
+		for j := range got {
+			if math.Abs(got[j].Distance-want[j]) > 1e-6 {
+				t.Fatalf("MeshIndex.Nearest(%v) distance[%d] = %v, want %v", geoCode, j, got[j].Distance, want[j])
+			}
+		}
+	}
+}
@@ -19,61 +19,134 @@ type GeoCode struct {
 // ToCode 緯度経度から地域メッシュコードを取得する。
 // 算出式 : https://www.stat.go.jp/data/mesh/pdf/gaiyo1.pdf
 func ToCode(geoCode GeoCode, level Level) (MeshCode, error) {
-	// （１）緯度よりｐ，ｑ，ｒ，ｓ，ｔを算出
-	p := math.Floor((geoCode.Latitude * 60) / 40)
-	a := math.Mod(geoCode.Latitude*60, 40)
-	q := math.Floor(a / 5)
-	b := math.Mod(a, 5)
-	r := math.Floor((b * 60) / 30)
-	c := math.Mod(b*60, 30)
-	s := math.Floor(c / 15)
-	d := math.Mod(c, 15)
-	t := math.Floor(d / 7.5)
-	// 以下、８分の１地域メッシュ算出のため拡張
-	ee := math.Mod(d, 7.5)
-	uu := math.Floor(ee / 3.75)
+	if _, ok := getMesh(level); !ok {
+		// レベルが不明な場合は全桁を返す
+		level = LevelOneEighth
+	}
+	var buf [maxCodeDigit]byte
+	code, err := AppendCode(buf[:0], geoCode, level)
+	if err != nil {
+		return "", err
+	}
+	return MeshCode(code), nil
+}
+
+// AppendCode 緯度経度から求めた地域メッシュコードを dst に追加する。
+// 指定したレベルまでの桁のみを求め、dst に十分な容量があればメモリを確保しない。
+func AppendCode(dst []byte, geoCode GeoCode, level Level) ([]byte, error) {
+	mesh, ok := getMesh(level)
+	if !ok {
+		return dst, ErrInvalidLevel
+	}
+	digit := mesh.Digit
 
-	// （２）経度よりｕ，ｖ，ｗ，ｘ，ｙを算出
+	// （１）緯度よりｐ，（２）経度よりｕを算出
+	p := math.Floor((geoCode.Latitude * 60) / 40)
 	u := math.Floor(geoCode.Longitude - 100)
+	if !(p >= 0 && p < 100 && u >= 0 && u < 100) || !isLevel1Area(int(p), int(u)) {
+		return dst, ErrInvalidArea
+	}
+	dst = append(dst, byte('0'+int(p)/10), byte('0'+int(p)%10), byte('0'+int(u)/10), byte('0'+int(u)%10))
+	if digit < level2Mesh.Digit {
+		return dst, nil
+	}
+
+	// ｑ，ｖを算出
+	a := mod(geoCode.Latitude*60, 40, p)
+	q := math.Floor(a / 5)
 	f := geoCode.Longitude - 100 - u
 	v := math.Floor((f * 60) / 7.5)
-	g := math.Mod(f*60, 7.5)
+	dst = append(dst, byte('0'+int(q)), byte('0'+int(v)))
+	if level == Level2 {
+		return dst, nil
+	}
+
+	// ｒ，ｗを算出
+	b := mod(a, 5, q)
+	r := math.Floor((b * 60) / 30)
+	g := mod(f*60, 7.5, v)
 	w := math.Floor((g * 60) / 45)
-	h := math.Mod(g*60, 45)
+	switch level {
+	case LevelFivefold:
+		// 基準地域メッシュの位置から 1:南西, 2:南東, 3:北西, 4:北東 を算出
+		return append(dst, byte('1'+int(r)/5*2+int(w)/5)), nil
+	case LevelTwofold:
+		// 南西端の基準地域メッシュの位置(偶数)と 5
+		return append(dst, byte('0'+int(r)/2*2), byte('0'+int(w)/2*2), '5'), nil
+	}
+	dst = append(dst, byte('0'+int(r)), byte('0'+int(w)))
+	if digit < levelHalfMesh.Digit {
+		return dst, nil
+	}
+
+	// ｓ，ｘよりｍを算出
+	c := mod(b*60, 30, r)
+	s := math.Floor(c / 15)
+	h := mod(g*60, 45, w)
 	x := math.Floor(h / 22.5)
-	i := math.Mod(h, 22.5)
+	dst = append(dst, byte('0'+int(s*2+(x+1))))
+	if digit < levelQuarterMesh.Digit {
+		return dst, nil
+	}
+
+	// ｔ，ｙよりｎを算出
+	d := mod(c, 15, s)
+	t := math.Floor(d / 7.5)
+	i := mod(h, 22.5, x)
 	y := math.Floor(i / 11.25)
-	// 以下、８分の１地域メッシュ算出のため拡張
-	jj := math.Mod(i, 11.25)
-	zz := math.Floor(jj / 5.625)
+	dst = append(dst, byte('0'+int(t*2+(y+1))))
+	if digit < levelOneEighthMesh.Digit {
+		return dst, nil
+	}
 
-	// （３）ｓ，ｘよりｍを算出，ｔ，ｙよりｎを算出
-	m := s*2 + (x + 1)
-	n := t*2 + (y + 1)
 	// 以下、８分の１地域メッシュ算出のため拡張
-	oo := uu*2 + (zz + 1)
+	ee := mod(d, 7.5, t)
+	uu := math.Floor(ee / 3.75)
+	jj := mod(i, 11.25, y)
+	zz := math.Floor(jj / 5.625)
+	dst = append(dst, byte('0'+int(uu*2+(zz+1))))
+	return dst, nil
+}
 
-	// （４）ｐ，ｑ，ｒ，ｕ，ｖ，ｗ，ｍ、ｎ、ooより地域メッシュ・コードを算出
-	code1 := Level1Code(fmt.Sprintf("%.f%.f", p, u))
-	if _, ok := level1Codes[code1]; !ok {
-		return "", ErrInvalidArea
+// mod 商 q(=math.Floor(x / y)) を使って math.Mod(x, y) を求める。
+// x, y は正の値で、y*q は誤差なく表せるため、減算の結果は math.Mod と一致する。
+func mod(x, y, q float64) float64 {
+	r := x - y*q
+	if r < 0 {
+		// x / y が丸めにより切り上がった場合
+		r = x - y*(q-1)
 	}
-	code := MeshCode(fmt.Sprintf("%.f%.f%.f%.f%.f%.f%.f%.f%.f", p, u, q, v, r, w, m, n, oo))
-	return getCodeByLevel(code, level), nil
+	return r
 }
 
 // ToGeoJSON
 func ToGeoJSON(code MeshCode, properties map[string]interface{}) (*geojson.Feature, error) {
+	bounds, err := ToBounds(code)
+	if err != nil {
+		return nil, err
+	}
+	return createGeoJSON(bounds.Min.Longitude, bounds.Max.Longitude, bounds.Min.Latitude, bounds.Max.Latitude, properties), nil
+}
+
+// ToBounds 指定した地域メッシュコードの範囲(南西端、北東端の緯度経度)を取得する。
+func ToBounds(code MeshCode) (Bounds, error) {
 	if !isValidCode(code) {
-		return nil, ErrInvalidMeshCode
+		return Bounds{}, ErrInvalidMeshCode
+	}
+	if level, _ := GetLevel(code); !isStandardLevel(level) {
+		c, err := toCell(code)
+		if err != nil {
+			return Bounds{}, err
+		}
+		return c.bounds(), nil
 	}
 	lv1X, err := strconv.ParseFloat(string(code[2:4]), 64)
 	if err != nil {
-		return nil, err
+		return Bounds{}, err
 	}
 	lv1Y, err := strconv.ParseFloat(string(code[0:2]), 64)
 	if err != nil {
-		return nil, err
+		return Bounds{}, err
 	}
 
 	var minX, maxX, minY, maxY float64
@@ -92,11 +165,11 @@ func ToGeoJSON(code MeshCode, properties map[string]interface{}) (*geojson.Featu
 	if digit >= level2Mesh.Digit {
 		lv2X, err := strconv.ParseFloat(string(code[5:6]), 64)
 		if err != nil {
-			return nil, err
+			return Bounds{}, err
 		}
 		lv2Y, err := strconv.ParseFloat(string(code[4:5]), 64)
 		if err != nil {
-			return nil, err
+			return Bounds{}, err
 		}
 		minX += lv2X * level2Mesh.Distance.Lng
 		maxX = minX + level2Mesh.Distance.Lng
@@ -107,11 +180,11 @@ func ToGeoJSON(code MeshCode, properties map[string]interface{}) (*geojson.Featu
 	if digit >= level3Mesh.Digit {
 		lv3X, err := strconv.ParseFloat(string(code[7:8]), 64)
 		if err != nil {
-			return nil, err
+			return Bounds{}, err
 		}
 		lv3Y, err := strconv.ParseFloat(string(code[6:7]), 64)
 		if err != nil {
-			return nil, err
+			return Bounds{}, err
 		}
 		minX += lv3X * level3Mesh.Distance.Lng
 		maxX = minX + level3Mesh.Distance.Lng
@@ -188,10 +261,15 @@ func ToGeoJSON(code MeshCode, properties map[string]interface{}) (*geojson.Featu
 		minY += lv6Y * levelOneEighthMesh.Distance.Lat
 		maxY = minY + levelOneEighthMesh.Distance.Lat
 	}
-	return createGeoJSON(minX, maxX, minY, maxY, properties), nil
+	return Bounds{
+		Min: GeoCode{Latitude: minY, Longitude: minX},
+		Max: GeoCode{Latitude: maxY, Longitude: maxX},
+	}, nil
 }
 
 // GetLevel
+// 9桁で末尾が 5 のコードは LevelHalf ではなく LevelTwofold、7桁のコードは LevelFivefold を返す。
+// 統合地域メッシュに対応する前はそれぞれ LevelHalf、ErrInvalidMeshCode を返していた。
 func GetLevel(code MeshCode) (Level, error) {
 	switch code.getDigit() {
 	case level1Mesh.Digit:
@@ -201,16 +279,40 @@ func GetLevel(code MeshCode) (Level, error) {
 	case level3Mesh.Digit:
 		return Level3, nil
 	case levelHalfMesh.Digit:
+		// 2倍地域メッシュは末尾が 5 で、2分の1地域メッシュ(1〜4)と区別できる
+		if code[levelTwofoldMesh.Digit-1] == '5' {
+			return LevelTwofold, nil
+		}
 		return LevelHalf, nil
 	case levelQuarterMesh.Digit:
 		return LevelQuarter, nil
 	case levelOneEighthMesh.Digit:
 		return LevelOneEighth, nil
+	case levelFivefoldMesh.Digit:
+		return LevelFivefold, nil
 	}
 	return "", ErrInvalidMeshCode
 }
 
+// ParseLevel "1"、"1/2"、"2x" などの文字列をレベルに変換する。対応するレベルがない場合は ErrInvalidLevel を返す。
+func ParseLevel(s string) (Level, error) {
+	if _, ok := getMesh(Level(s)); !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLevel, s)
+	}
+	return Level(s), nil
+}
+
+// GetMesh レベルに対応する地域メッシュの定義(桁数、分割数、緯度経度の間隔)を取得する。
+func GetMesh(level Level) (Mesh, error) {
+	mesh, ok := getMesh(level)
+	if !ok {
+		return Mesh{}, ErrInvalidLevel
+	}
+	return mesh, nil
+}
+
 // GetCodes
+// 1/8地域メッシュと統合地域メッシュは下位のレベルを持たないため ErrInvalidLevel を返す。
 func GetCodes(code MeshCode) (MeshCodes, error) {
 	if !isValidCode(code) {
 		return nil, ErrInvalidMeshCode
@@ -220,6 +322,10 @@ func GetCodes(code MeshCode) (MeshCodes, error) {
 	if err != nil {
 		return nil, err
 	}
+	if !isStandardLevel(level) || level == LevelOneEighth {
+		// 統合地域メッシュと1/8地域メッシュは下位のレベルを持たない
+		return nil, ErrInvalidLevel
+	}
 	switch level {
 	case Level1:
 		// 2次メッシュ
@@ -235,7 +341,7 @@ func GetCodes(code MeshCode) (MeshCodes, error) {
 				codes = append(codes, MeshCode(fmt.Sprintf("%s%d%d", code, y3, x3)))
 			}
 		}
-	case Level3, LevelHalf, LevelQuarter, LevelOneEighth:
+	case Level3, LevelHalf, LevelQuarter:
 		// 4次,5次,6次メッシュ
 		divisionNum := 4 // 分割数(=マスの数)
 		for i := 1; i <= divisionNum; i++ {
@@ -249,6 +355,10 @@ func GetCodes(code MeshCode) (MeshCodes, error) {
 // SplitCodeByLevel
 func SplitCodeByLevel(code MeshCode) []MeshCode {
 	var codes []MeshCode
+	if level, err := GetLevel(code); err == nil && !isStandardLevel(level) {
+		// 統合地域メッシュは第1次、第2次地域区画の配下にある
+		return []MeshCode{getCodeByLevel(code, Level1), getCodeByLevel(code, Level2), code}
+	}
 	digit := code.getDigit()
 	if digit >= level1Mesh.Digit {
 		codes = append(codes, getCodeByLevel(code, Level1))
@@ -271,6 +381,89 @@ func SplitCodeByLevel(code MeshCode) []MeshCode {
 	return codes
 }
 
+// GetParent 指定した地域メッシュコードの直上のレベルの地域メッシュコードを取得する。
+// 第1次地域区画には上位のレベルがないため ErrInvalidLevel を返す。
+func GetParent(code MeshCode) (MeshCode, error) {
+	c, err := toCell(code)
+	if err != nil {
+		return "", err
+	}
+	parent, ok := c.parent()
+	if !ok {
+		return "", ErrInvalidLevel
+	}
+	return parent.toCode()
+}
+
+// GetNeighbors 指定した地域メッシュコードに隣接する、同じレベルの地域メッシュコードを北から時計回りに取得する。
+// 第1次地域区画の境界をまたぐ場合も隣接するメッシュを返すが、日本の国土にかからない区画のメッシュは含まない。
+func GetNeighbors(code MeshCode) (MeshCodes, error) {
+	c, err := toCell(code)
+	if err != nil {
+		return nil, err
+	}
+	codes := make(MeshCodes, 0, len(neighborOffsets))
+	for _, offset := range neighborOffsets {
+		neighbor, err := cell{level: c.level, y: c.y + offset[0], x: c.x + offset[1]}.toCode()
+		if err != nil {
+			continue
+		}
+		codes = append(codes, neighbor)
+	}
+	return codes, nil
+}
+
+// Validate 地域メッシュコードが規格に沿った値であるかを検証する。
+// 桁数に加えて、第1次地域区画が日本の国土にかかる区画であること、各桁の数字が取りうる範囲内であることを確認する。
+// 統合地域メッシュ(5x, 2x)のコードも有効なコードとして扱う。以前のバージョンではエラーを返していた。
+func Validate(code MeshCode) error {
+	if !isValidCode(code) {
+		return ErrInvalidMeshCode
+	}
+	var digits [maxCodeDigit]byte
+	n := copy(digits[:], code)
+	return validateDigits(digits[:n])
+}
+
+// validateDigits 桁数が正しいメッシュコードの各桁を検証する。
+func validateDigits(digits []byte) error {
+	for _, d := range digits {
+		if d < '0' || d > '9' {
+			return ErrInvalidMeshCode
+		}
+	}
+	if !isLevel1Area(int(digits[0]-'0')*10+int(digits[1]-'0'), int(digits[2]-'0')*10+int(digits[3]-'0')) {
+		return ErrInvalidArea
+	}
+	if len(digits) >= level2Mesh.Digit {
+		// 第2次地域区画は 0〜7 の8分割
+		if digits[4] > '7' || digits[5] > '7' {
+			return ErrInvalidMeshCode
+		}
+	}
+	switch {
+	case len(digits) == levelFivefoldMesh.Digit:
+		// 5倍地域メッシュは 1〜4 の4分割
+		if digits[6] < '1' || digits[6] > '4' {
+			return ErrInvalidMeshCode
+		}
+		return nil
+	case len(digits) == levelTwofoldMesh.Digit && digits[8] == '5':
+		// 2倍地域メッシュは基準地域メッシュの偶数の位置
+		if digits[6]%2 != 0 || digits[7]%2 != 0 {
+			return ErrInvalidMeshCode
+		}
+		return nil
+	}
+	for i := levelHalfMesh.Digit - 1; i < len(digits); i++ {
+		// 2分の1地域メッシュ以下は 1〜4 の4分割
+		if digits[i] < '1' || digits[i] > '4' {
+			return ErrInvalidMeshCode
+		}
+	}
+	return nil
+}
+
 func isValidCode(code MeshCode) bool {
 	switch code.getDigit() {
 	case
@@ -279,12 +472,45 @@ func isValidCode(code MeshCode) bool {
 		level3Mesh.Digit,
 		levelHalfMesh.Digit,
 		levelQuarterMesh.Digit,
-		levelOneEighthMesh.Digit:
+		levelOneEighthMesh.Digit,
+		levelFivefoldMesh.Digit:
+		return true
+	}
+	return false
+}
+
+// isStandardLevel 統合地域メッシュではない、標準地域メッシュ・分割地域メッシュのレベルであるかを判定する。
+func isStandardLevel(level Level) bool {
+	switch level {
+	case Level1, Level2, Level3, LevelHalf, LevelQuarter, LevelOneEighth:
 		return true
 	}
 	return false
 }
 
+// isAncestorLevel ancestor のメッシュコードの先頭の桁が、level のメッシュコードの上位のメッシュ(または同じメッシュ)を表すかを判定する。
+func isAncestorLevel(ancestor, level Level) bool {
+	if ancestor == level {
+		return true
+	}
+	a, ok := getMesh(ancestor)
+	if !ok {
+		return false
+	}
+	m, ok := getMesh(level)
+	if !ok {
+		return false
+	}
+	if !isStandardLevel(ancestor) {
+		return false
+	}
+	if !isStandardLevel(level) {
+		// 統合地域メッシュは第2次地域区画の配下
+		return a.Digit <= level2Mesh.Digit
+	}
+	return a.Digit < m.Digit
+}
+
 func getCodeByLevel(code MeshCode, level Level) MeshCode {
 	switch level {
 	case Level1:
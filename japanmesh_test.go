@@ -2,6 +2,10 @@ package japanmesh
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -133,6 +137,9 @@ func TestGetLevel(t *testing.T) {
 		{name: "level1-2", args: args{code: "533945471"}, want: LevelHalf, wantErr: false},
 		{name: "level1-4", args: args{code: "5339454711"}, want: LevelQuarter, wantErr: false},
 		{name: "level1-8", args: args{code: "53394547112"}, want: LevelOneEighth, wantErr: false},
+		// 統合地域メッシュ対応前は LevelHalf / エラーを返していた
+		{name: "twofold", args: args{code: "533945465"}, want: LevelTwofold, wantErr: false},
+		{name: "fivefold", args: args{code: "5339454"}, want: LevelFivefold, wantErr: false},
 		{name: "level1-8", args: args{code: "1"}, want: "", wantErr: true},
 	}
 	for _, tt := range tests {
@@ -149,6 +156,60 @@ func TestGetLevel(t *testing.T) {
 	}
 }
 
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Level
+		wantErr error
+	}{
+		{s: "1", want: Level1},
+		{s: "3", want: Level3},
+		{s: "1/8", want: LevelOneEighth},
+		{s: "2x", want: LevelTwofold},
+		{s: "5x", want: LevelFivefold},
+		{s: "4", wantErr: ErrInvalidLevel},
+		{s: "", wantErr: ErrInvalidLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseLevel(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseLevel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMesh(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   Level
+		want    Distance
+		wantErr error
+	}{
+		{name: "level2", level: Level2, want: Distance{Lat: float64(5) / 60, Lng: float64(7.5) / 60}},
+		{name: "level3", level: Level3, want: Distance{Lat: float64(30) / 3600, Lng: float64(45) / 3600}},
+		{name: "twofold", level: LevelTwofold, want: Distance{Lat: float64(1) / 60, Lng: float64(1.5) / 60}},
+		{name: "invalid", level: "4", wantErr: ErrInvalidLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetMesh(tt.level)
+			if err != tt.wantErr {
+				t.Errorf("GetMesh() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if math.Abs(got.Distance.Lat-tt.want.Lat) > 1e-12 || math.Abs(got.Distance.Lng-tt.want.Lng) > 1e-12 {
+				t.Errorf("GetMesh() distance = %v, want %v", got.Distance, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetCodes(t *testing.T) {
 	lv2Codes := []MeshCode{
 		"533900", "533901", "533902", "533903", "533904", "533905", "533906", "533907",
@@ -196,6 +257,7 @@ func TestGetCodes(t *testing.T) {
 		{name: "level3->level1-2list", args: args{code: "53394547"}, want: lvHalfCodes, wantErr: false},
 		{name: "level1-2->level1-4list", args: args{code: "533945471"}, want: lvQuarterCodes, wantErr: false},
 		{name: "level1-4->level1-8list", args: args{code: "5339454711"}, want: lvOneEightCodes, wantErr: false},
+		{name: "level1-8 has no lower level", args: args{code: "53394547112"}, want: nil, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -236,3 +298,328 @@ func TestSplitCodeByLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate(t *testing.T) {
+	type args struct {
+		code MeshCode
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{name: "level1", args: args{code: "5339"}, wantErr: nil},
+		{name: "level2", args: args{code: "533945"}, wantErr: nil},
+		{name: "level3", args: args{code: "53394547"}, wantErr: nil},
+		{name: "level1-2", args: args{code: "533945471"}, wantErr: nil},
+		{name: "level1-4", args: args{code: "5339454711"}, wantErr: nil},
+		{name: "level1-8", args: args{code: "53394547112"}, wantErr: nil},
+		// 統合地域メッシュ対応前は ErrInvalidMeshCode を返していた
+		{name: "twofold", args: args{code: "533945465"}, wantErr: nil},
+		{name: "fivefold", args: args{code: "5339454"}, wantErr: nil},
+		{name: "invalid digit", args: args{code: "1"}, wantErr: ErrInvalidMeshCode},
+		{name: "not number", args: args{code: "53a9"}, wantErr: ErrInvalidMeshCode},
+		{name: "out of area", args: args{code: "0000"}, wantErr: ErrInvalidArea},
+		{name: "level2 out of range", args: args{code: "533985"}, wantErr: ErrInvalidMeshCode},
+		{name: "level1-2 out of range", args: args{code: "533945475"}, wantErr: ErrInvalidMeshCode},
+		{name: "level1-8 out of range", args: args{code: "53394547110"}, wantErr: ErrInvalidMeshCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.args.code); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetParent(t *testing.T) {
+	type args struct {
+		code MeshCode
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    MeshCode
+		wantErr bool
+	}{
+		{name: "level1", args: args{code: "5339"}, want: "", wantErr: true},
+		{name: "level2", args: args{code: "533945"}, want: "5339", wantErr: false},
+		{name: "level3", args: args{code: "53394547"}, want: "533945", wantErr: false},
+		{name: "level1-2", args: args{code: "533945471"}, want: "53394547", wantErr: false},
+		{name: "level1-4", args: args{code: "5339454711"}, want: "533945471", wantErr: false},
+		{name: "level1-8", args: args{code: "53394547112"}, want: "5339454711", wantErr: false},
+		{name: "invalid", args: args{code: "1"}, want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetParent(tt.args.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetParent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetParent() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNeighbors(t *testing.T) {
+	type args struct {
+		code MeshCode
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    MeshCodes
+		wantErr bool
+	}{
+		{
+			name: "level3",
+			args: args{code: "53394547"},
+			want: MeshCodes{"53394557", "53394558", "53394548", "53394538", "53394537", "53394536", "53394546", "53394556"},
+		},
+		{
+			name: "level2 across level1",
+			args: args{code: "533977"},
+			want: MeshCodes{"543907", "544000", "534070", "534060", "533967", "533966", "533976", "543906"},
+		},
+		{
+			name: "level1-2 across level3",
+			args: args{code: "533945474"},
+			want: MeshCodes{"533945572", "533945581", "533945483", "533945481", "533945472", "533945471", "533945473", "533945571"},
+		},
+		{
+			name: "out of area",
+			args: args{code: "3036"},
+			want: MeshCodes{},
+		},
+		{name: "invalid", args: args{code: "1"}, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetNeighbors(tt.args.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetNeighbors() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetNeighbors() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendCode(t *testing.T) {
+	geoCode := GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	tests := []struct {
+		name    string
+		geoCode GeoCode
+		level   Level
+		want    string
+		wantErr error
+	}{
+		{name: "level1", geoCode: geoCode, level: Level1, want: "5339", wantErr: nil},
+		{name: "level3", geoCode: geoCode, level: Level3, want: "53394547", wantErr: nil},
+		{name: "level1-8", geoCode: geoCode, level: LevelOneEighth, want: "53394547112", wantErr: nil},
+		{name: "invalid level", geoCode: geoCode, level: "4", want: "", wantErr: ErrInvalidLevel},
+		{name: "invalid area", geoCode: GeoCode{Latitude: 0, Longitude: 0}, level: Level3, want: "", wantErr: ErrInvalidArea},
+		{name: "NaN", geoCode: GeoCode{Latitude: math.NaN(), Longitude: 139.71475}, level: Level3, want: "", wantErr: ErrInvalidArea},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AppendCode(nil, tt.geoCode, tt.level)
+			if err != tt.wantErr {
+				t.Errorf("AppendCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("AppendCode() got = %s, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// toCodeSprintf 算出式どおりに全桁を求めて fmt.Sprintf で組み立てる、ToCode の従来の実装
+func toCodeSprintf(geoCode GeoCode) MeshCode {
+	p := math.Floor((geoCode.Latitude * 60) / 40)
+	a := math.Mod(geoCode.Latitude*60, 40)
+	q := math.Floor(a / 5)
+	b := math.Mod(a, 5)
+	r := math.Floor((b * 60) / 30)
+	c := math.Mod(b*60, 30)
+	s := math.Floor(c / 15)
+	d := math.Mod(c, 15)
+	t := math.Floor(d / 7.5)
+	uu := math.Floor(math.Mod(d, 7.5) / 3.75)
+	u := math.Floor(geoCode.Longitude - 100)
+	f := geoCode.Longitude - 100 - u
+	v := math.Floor((f * 60) / 7.5)
+	g := math.Mod(f*60, 7.5)
+	w := math.Floor((g * 60) / 45)
+	h := math.Mod(g*60, 45)
+	x := math.Floor(h / 22.5)
+	i := math.Mod(h, 22.5)
+	y := math.Floor(i / 11.25)
+	zz := math.Floor(math.Mod(i, 11.25) / 5.625)
+	return MeshCode(fmt.Sprintf("%.f%.f%.f%.f%.f%.f%.f%.f%.f", p, u, q, v, r, w, s*2+(x+1), t*2+(y+1), uu*2+(zz+1)))
+}
+
+func TestToCode_CompatibleWithSprintf(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		geoCode := GeoCode{Latitude: 24 + rnd.Float64()*22, Longitude: 123 + rnd.Float64()*23}
+		want := toCodeSprintf(geoCode)
+		if _, ok := level1Codes[Level1Code(want[:4])]; !ok {
+			continue
+		}
+		got, err := ToCode(geoCode, LevelOneEighth)
+		if err != nil || got != want {
+			t.Fatalf("ToCode(%v) got = %v, %v, want %v", geoCode, got, err, want)
+		}
+	}
+}
+
+func TestAppendCode_Allocs(t *testing.T) {
+	geoCode := GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	buf := make([]byte, 0, maxCodeDigit)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = AppendCode(buf[:0], geoCode, LevelOneEighth)
+	})
+	if allocs != 0 {
+		t.Errorf("AppendCode() allocs = %v, want 0", allocs)
+	}
+	allocs = testing.AllocsPerRun(100, func() {
+		_, _ = ToMeshID(geoCode, LevelOneEighth)
+	})
+	if allocs != 0 {
+		t.Errorf("ToMeshID() allocs = %v, want 0", allocs)
+	}
+}
+
+var benchmarkLevels = []struct {
+	name  string
+	level Level
+}{
+	{name: "level1", level: Level1},
+	{name: "level3", level: Level3},
+	{name: "level1-8", level: LevelOneEighth},
+}
+
+func BenchmarkToCode(b *testing.B) {
+	geoCode := GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	for _, bb := range benchmarkLevels {
+		level := bb.level
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = ToCode(geoCode, level)
+			}
+		})
+	}
+}
+
+func BenchmarkAppendCode(b *testing.B) {
+	geoCode := GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	buf := make([]byte, 0, maxCodeDigit)
+	for _, bb := range benchmarkLevels {
+		level := bb.level
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf, _ = AppendCode(buf[:0], geoCode, level)
+			}
+		})
+	}
+}
+
+func BenchmarkToMeshID(b *testing.B) {
+	geoCode := GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	for _, bb := range benchmarkLevels {
+		level := bb.level
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = ToMeshID(geoCode, level)
+			}
+		})
+	}
+}
+
+func TestIntegratedMesh(t *testing.T) {
+	geoCode := GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	tests := []struct {
+		name   string
+		level  Level
+		code   MeshCode
+		bounds Bounds
+		parent MeshCode
+	}{
+		{
+			name:   "fivefold",
+			level:  LevelFivefold,
+			code:   "5339452",
+			bounds: Bounds{Min: GeoCode{Latitude: 35.666666666666664, Longitude: 139.6875}, Max: GeoCode{Latitude: 35.708333333333336, Longitude: 139.75}},
+			parent: "533945",
+		},
+		{
+			name:   "twofold",
+			level:  LevelTwofold,
+			code:   "533945465",
+			bounds: Bounds{Min: GeoCode{Latitude: 35.7, Longitude: 139.7}, Max: GeoCode{Latitude: 35.71666666666667, Longitude: 139.725}},
+			parent: "533945",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := ToCode(geoCode, tt.level)
+			if err != nil || code != tt.code {
+				t.Fatalf("ToCode() got = %v, %v, want %v", code, err, tt.code)
+			}
+			if level, _ := GetLevel(code); level != tt.level {
+				t.Errorf("GetLevel() got = %v, want %v", level, tt.level)
+			}
+			if err := Validate(code); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+			bounds, err := ToBounds(code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(bounds.Min.Latitude-tt.bounds.Min.Latitude) > 1e-12 ||
+				math.Abs(bounds.Min.Longitude-tt.bounds.Min.Longitude) > 1e-12 ||
+				math.Abs(bounds.Max.Latitude-tt.bounds.Max.Latitude) > 1e-12 ||
+				math.Abs(bounds.Max.Longitude-tt.bounds.Max.Longitude) > 1e-12 {
+				t.Errorf("ToBounds() got = %v, want %v", bounds, tt.bounds)
+			}
+			if !bounds.Contains(geoCode) {
+				t.Errorf("ToBounds() got = %v, not contains %v", bounds, geoCode)
+			}
+			if parent, _ := GetParent(code); parent != tt.parent {
+				t.Errorf("GetParent() got = %v, want %v", parent, tt.parent)
+			}
+			if codes := SplitCodeByLevel(code); !reflect.DeepEqual(codes, []MeshCode{"5339", "533945", code}) {
+				t.Errorf("SplitCodeByLevel() got = %v", codes)
+			}
+			if _, err := GetCodes(code); err != ErrInvalidLevel {
+				t.Errorf("GetCodes() error = %v, wantErr %v", err, ErrInvalidLevel)
+			}
+			id, err := NewMeshID(code)
+			if err != nil || id.MeshCode() != code || id.Level() != tt.level {
+				t.Errorf("NewMeshID() got = %v, %v", id, err)
+			}
+		})
+	}
+
+	for _, code := range []MeshCode{"5339455", "533945475", "533945455"} {
+		if err := Validate(code); err != ErrInvalidMeshCode {
+			t.Errorf("Validate(%v) error = %v, wantErr %v", code, err, ErrInvalidMeshCode)
+		}
+	}
+	neighbors, _ := GetNeighbors("533947885")
+	want := MeshCodes{"533957085", "534050005", "534040805", "534040605", "533947685", "533947665", "533947865", "533957065"}
+	if !reflect.DeepEqual(neighbors, want) {
+		t.Errorf("GetNeighbors() got = %v, want %v", neighbors, want)
+	}
+}
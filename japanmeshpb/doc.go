@@ -0,0 +1,6 @@
+// Package japanmeshpb は地域メッシュコードを変換・検索する gRPC サービス(japanmesh.proto)から生成したコード。
+//
+// japanmesh.proto を変更した場合は protoc-gen-go v1.33.0、protoc-gen-go-grpc v1.3.0 で再生成する。
+//
+//go:generate protoc -I.. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative ../japanmeshpb/japanmesh.proto
+package japanmeshpb
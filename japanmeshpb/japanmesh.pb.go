@@ -0,0 +1,1059 @@
+// 地域メッシュコードを変換・検索する gRPC サービス。
+// レベルは "1"、"2"、"3"、"1/2"、"1/4"、"1/8"、"2x"、"5x" のいずれかで、省略した場合は "3" とする。
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: japanmeshpb/japanmesh.proto
+
+package japanmeshpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CoverMode 図形に含める地域メッシュの判定方法
+type CoverMode int32
+
+const (
+	// 図形と重なる部分があるメッシュ(COVER_MODE_INTERSECTS と同じ)
+	CoverMode_COVER_MODE_UNSPECIFIED CoverMode = 0
+	// 図形と重なる部分があるメッシュ
+	CoverMode_COVER_MODE_INTERSECTS CoverMode = 1
+	// 全体が図形に含まれるメッシュ
+	CoverMode_COVER_MODE_CONTAINS CoverMode = 2
+	// 中心が図形に含まれるメッシュ
+	CoverMode_COVER_MODE_CENTER CoverMode = 3
+)
+
+// Enum value maps for CoverMode.
+var (
+	CoverMode_name = map[int32]string{
+		0: "COVER_MODE_UNSPECIFIED",
+		1: "COVER_MODE_INTERSECTS",
+		2: "COVER_MODE_CONTAINS",
+		3: "COVER_MODE_CENTER",
+	}
+	CoverMode_value = map[string]int32{
+		"COVER_MODE_UNSPECIFIED": 0,
+		"COVER_MODE_INTERSECTS":  1,
+		"COVER_MODE_CONTAINS":    2,
+		"COVER_MODE_CENTER":      3,
+	}
+)
+
+func (x CoverMode) Enum() *CoverMode {
+	p := new(CoverMode)
+	*p = x
+	return p
+}
+
+func (x CoverMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CoverMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_japanmeshpb_japanmesh_proto_enumTypes[0].Descriptor()
+}
+
+func (CoverMode) Type() protoreflect.EnumType {
+	return &file_japanmeshpb_japanmesh_proto_enumTypes[0]
+}
+
+func (x CoverMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CoverMode.Descriptor instead.
+func (CoverMode) EnumDescriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{0}
+}
+
+// LatLng 緯度経度(度)
+type LatLng struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latitude  float64 `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64 `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+}
+
+func (x *LatLng) Reset() {
+	*x = LatLng{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LatLng) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatLng) ProtoMessage() {}
+
+func (x *LatLng) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatLng.ProtoReflect.Descriptor instead.
+func (*LatLng) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LatLng) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *LatLng) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+// Bounds 地域メッシュの範囲
+type Bounds struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 南西端
+	Min *LatLng `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
+	// 北東端
+	Max *LatLng `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *Bounds) Reset() {
+	*x = Bounds{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Bounds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bounds) ProtoMessage() {}
+
+func (x *Bounds) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bounds.ProtoReflect.Descriptor instead.
+func (*Bounds) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Bounds) GetMin() *LatLng {
+	if x != nil {
+		return x.Min
+	}
+	return nil
+}
+
+func (x *Bounds) GetMax() *LatLng {
+	if x != nil {
+		return x.Max
+	}
+	return nil
+}
+
+type EncodeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Point *LatLng `protobuf:"bytes,1,opt,name=point,proto3" json:"point,omitempty"`
+	Level string  `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+}
+
+func (x *EncodeRequest) Reset() {
+	*x = EncodeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncodeRequest) ProtoMessage() {}
+
+func (x *EncodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncodeRequest.ProtoReflect.Descriptor instead.
+func (*EncodeRequest) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *EncodeRequest) GetPoint() *LatLng {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+func (x *EncodeRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type EncodeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code  string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Level string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	// EncodeStream で変換できなかった場合のエラー。code は空とする
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *EncodeResponse) Reset() {
+	*x = EncodeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncodeResponse) ProtoMessage() {}
+
+func (x *EncodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncodeResponse.ProtoReflect.Descriptor instead.
+func (*EncodeResponse) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EncodeResponse) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *EncodeResponse) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *EncodeResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DecodeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *DecodeRequest) Reset() {
+	*x = DecodeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeRequest) ProtoMessage() {}
+
+func (x *DecodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeRequest.ProtoReflect.Descriptor instead.
+func (*DecodeRequest) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DecodeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type DecodeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code   string  `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Level  string  `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	Bounds *Bounds `protobuf:"bytes,3,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	Center *LatLng `protobuf:"bytes,4,opt,name=center,proto3" json:"center,omitempty"`
+}
+
+func (x *DecodeResponse) Reset() {
+	*x = DecodeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeResponse) ProtoMessage() {}
+
+func (x *DecodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeResponse.ProtoReflect.Descriptor instead.
+func (*DecodeResponse) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DecodeResponse) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *DecodeResponse) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *DecodeResponse) GetBounds() *Bounds {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *DecodeResponse) GetCenter() *LatLng {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+type ChildrenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *ChildrenRequest) Reset() {
+	*x = ChildrenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChildrenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChildrenRequest) ProtoMessage() {}
+
+func (x *ChildrenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChildrenRequest.ProtoReflect.Descriptor instead.
+func (*ChildrenRequest) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ChildrenRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ChildrenResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Codes []string `protobuf:"bytes,1,rep,name=codes,proto3" json:"codes,omitempty"`
+}
+
+func (x *ChildrenResponse) Reset() {
+	*x = ChildrenResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChildrenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChildrenResponse) ProtoMessage() {}
+
+func (x *ChildrenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChildrenResponse.ProtoReflect.Descriptor instead.
+func (*ChildrenResponse) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ChildrenResponse) GetCodes() []string {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+type NeighborsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *NeighborsRequest) Reset() {
+	*x = NeighborsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NeighborsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NeighborsRequest) ProtoMessage() {}
+
+func (x *NeighborsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NeighborsRequest.ProtoReflect.Descriptor instead.
+func (*NeighborsRequest) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *NeighborsRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type NeighborsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Codes []string `protobuf:"bytes,1,rep,name=codes,proto3" json:"codes,omitempty"`
+}
+
+func (x *NeighborsResponse) Reset() {
+	*x = NeighborsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NeighborsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NeighborsResponse) ProtoMessage() {}
+
+func (x *NeighborsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NeighborsResponse.ProtoReflect.Descriptor instead.
+func (*NeighborsResponse) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *NeighborsResponse) GetCodes() []string {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+type CoverRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// GeoJSON の Geometry(Polygon、MultiPolygon、Point、MultiPoint)
+	Geojson string    `protobuf:"bytes,1,opt,name=geojson,proto3" json:"geojson,omitempty"`
+	Level   string    `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	Mode    CoverMode `protobuf:"varint,3,opt,name=mode,proto3,enum=japanmesh.v1.CoverMode" json:"mode,omitempty"`
+}
+
+func (x *CoverRequest) Reset() {
+	*x = CoverRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CoverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoverRequest) ProtoMessage() {}
+
+func (x *CoverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoverRequest.ProtoReflect.Descriptor instead.
+func (*CoverRequest) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CoverRequest) GetGeojson() string {
+	if x != nil {
+		return x.Geojson
+	}
+	return ""
+}
+
+func (x *CoverRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *CoverRequest) GetMode() CoverMode {
+	if x != nil {
+		return x.Mode
+	}
+	return CoverMode_COVER_MODE_UNSPECIFIED
+}
+
+type CoverResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// メッシュコードの昇順
+	Codes []string `protobuf:"bytes,1,rep,name=codes,proto3" json:"codes,omitempty"`
+}
+
+func (x *CoverResponse) Reset() {
+	*x = CoverResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_japanmeshpb_japanmesh_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CoverResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoverResponse) ProtoMessage() {}
+
+func (x *CoverResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_japanmeshpb_japanmesh_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoverResponse.ProtoReflect.Descriptor instead.
+func (*CoverResponse) Descriptor() ([]byte, []int) {
+	return file_japanmeshpb_japanmesh_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CoverResponse) GetCodes() []string {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+var File_japanmeshpb_japanmesh_proto protoreflect.FileDescriptor
+
+var file_japanmeshpb_japanmesh_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x70, 0x62, 0x2f, 0x6a, 0x61,
+	0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x6a,
+	0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x22, 0x42, 0x0a, 0x06, 0x4c,
+	0x61, 0x74, 0x4c, 0x6e, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22,
+	0x58, 0x0a, 0x06, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x03, 0x6d, 0x69, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65,
+	0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x74, 0x4c, 0x6e, 0x67, 0x52, 0x03, 0x6d, 0x69,
+	0x6e, 0x12, 0x26, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61,
+	0x74, 0x4c, 0x6e, 0x67, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x22, 0x51, 0x0a, 0x0d, 0x45, 0x6e, 0x63,
+	0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x05, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6a, 0x61, 0x70, 0x61,
+	0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x74, 0x4c, 0x6e, 0x67, 0x52,
+	0x05, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x50, 0x0a, 0x0e,
+	0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x23,
+	0x0a, 0x0d, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63,
+	0x6f, 0x64, 0x65, 0x22, 0x96, 0x01, 0x0a, 0x0e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65,
+	0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c,
+	0x12, 0x2c, 0x0a, 0x06, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e,
+	0x42, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x52, 0x06, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x2c,
+	0x0a, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61,
+	0x74, 0x4c, 0x6e, 0x67, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x22, 0x25, 0x0a, 0x0f,
+	0x43, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63,
+	0x6f, 0x64, 0x65, 0x22, 0x28, 0x0a, 0x10, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x26, 0x0a,
+	0x10, 0x4e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x29, 0x0a, 0x11, 0x4e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f,
+	0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f,
+	0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x73,
+	0x22, 0x6b, 0x0a, 0x0c, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x67, 0x65, 0x6f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x67, 0x65, 0x6f, 0x6a, 0x73, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65,
+	0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c,
+	0x12, 0x2b, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17,
+	0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x76, 0x65, 0x72, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0x25, 0x0a,
+	0x0d, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63,
+	0x6f, 0x64, 0x65, 0x73, 0x2a, 0x72, 0x0a, 0x09, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x4d, 0x6f, 0x64,
+	0x65, 0x12, 0x1a, 0x0a, 0x16, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a,
+	0x15, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x54, 0x45,
+	0x52, 0x53, 0x45, 0x43, 0x54, 0x53, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x43, 0x4f, 0x56, 0x45,
+	0x52, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x54, 0x41, 0x49, 0x4e, 0x53, 0x10,
+	0x02, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f,
+	0x43, 0x45, 0x4e, 0x54, 0x45, 0x52, 0x10, 0x03, 0x32, 0xc1, 0x03, 0x0a, 0x0b, 0x4d, 0x65, 0x73,
+	0x68, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x43, 0x0a, 0x06, 0x45, 0x6e, 0x63, 0x6f,
+	0x64, 0x65, 0x12, 0x1b, 0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x45,
+	0x6e, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a,
+	0x0c, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1b, 0x2e,
+	0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x63,
+	0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6a, 0x61, 0x70,
+	0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x12, 0x43, 0x0a, 0x06,
+	0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65,
+	0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x49, 0x0a, 0x08, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x12, 0x1d, 0x2e,
+	0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x69,
+	0x6c, 0x64, 0x72, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6a,
+	0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x69, 0x6c,
+	0x64, 0x72, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x09,
+	0x4e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x12, 0x1e, 0x2e, 0x6a, 0x61, 0x70, 0x61,
+	0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f,
+	0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x6a, 0x61, 0x70, 0x61,
+	0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f,
+	0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x05, 0x43, 0x6f,
+	0x76, 0x65, 0x72, 0x12, 0x1a, 0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x6f, 0x76, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x3d, 0x5a, 0x3b,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x65, 0x69, 0x74, 0x61,
+	0x72, 0x6f, 0x31, 0x30, 0x32, 0x30, 0x2f, 0x67, 0x6f, 0x2d, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d,
+	0x65, 0x73, 0x68, 0x2f, 0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x70, 0x62, 0x3b,
+	0x6a, 0x61, 0x70, 0x61, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_japanmeshpb_japanmesh_proto_rawDescOnce sync.Once
+	file_japanmeshpb_japanmesh_proto_rawDescData = file_japanmeshpb_japanmesh_proto_rawDesc
+)
+
+func file_japanmeshpb_japanmesh_proto_rawDescGZIP() []byte {
+	file_japanmeshpb_japanmesh_proto_rawDescOnce.Do(func() {
+		file_japanmeshpb_japanmesh_proto_rawDescData = protoimpl.X.CompressGZIP(file_japanmeshpb_japanmesh_proto_rawDescData)
+	})
+	return file_japanmeshpb_japanmesh_proto_rawDescData
+}
+
+var file_japanmeshpb_japanmesh_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_japanmeshpb_japanmesh_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_japanmeshpb_japanmesh_proto_goTypes = []interface{}{
+	(CoverMode)(0),            // 0: japanmesh.v1.CoverMode
+	(*LatLng)(nil),            // 1: japanmesh.v1.LatLng
+	(*Bounds)(nil),            // 2: japanmesh.v1.Bounds
+	(*EncodeRequest)(nil),     // 3: japanmesh.v1.EncodeRequest
+	(*EncodeResponse)(nil),    // 4: japanmesh.v1.EncodeResponse
+	(*DecodeRequest)(nil),     // 5: japanmesh.v1.DecodeRequest
+	(*DecodeResponse)(nil),    // 6: japanmesh.v1.DecodeResponse
+	(*ChildrenRequest)(nil),   // 7: japanmesh.v1.ChildrenRequest
+	(*ChildrenResponse)(nil),  // 8: japanmesh.v1.ChildrenResponse
+	(*NeighborsRequest)(nil),  // 9: japanmesh.v1.NeighborsRequest
+	(*NeighborsResponse)(nil), // 10: japanmesh.v1.NeighborsResponse
+	(*CoverRequest)(nil),      // 11: japanmesh.v1.CoverRequest
+	(*CoverResponse)(nil),     // 12: japanmesh.v1.CoverResponse
+}
+var file_japanmeshpb_japanmesh_proto_depIdxs = []int32{
+	1,  // 0: japanmesh.v1.Bounds.min:type_name -> japanmesh.v1.LatLng
+	1,  // 1: japanmesh.v1.Bounds.max:type_name -> japanmesh.v1.LatLng
+	1,  // 2: japanmesh.v1.EncodeRequest.point:type_name -> japanmesh.v1.LatLng
+	2,  // 3: japanmesh.v1.DecodeResponse.bounds:type_name -> japanmesh.v1.Bounds
+	1,  // 4: japanmesh.v1.DecodeResponse.center:type_name -> japanmesh.v1.LatLng
+	0,  // 5: japanmesh.v1.CoverRequest.mode:type_name -> japanmesh.v1.CoverMode
+	3,  // 6: japanmesh.v1.MeshService.Encode:input_type -> japanmesh.v1.EncodeRequest
+	3,  // 7: japanmesh.v1.MeshService.EncodeStream:input_type -> japanmesh.v1.EncodeRequest
+	5,  // 8: japanmesh.v1.MeshService.Decode:input_type -> japanmesh.v1.DecodeRequest
+	7,  // 9: japanmesh.v1.MeshService.Children:input_type -> japanmesh.v1.ChildrenRequest
+	9,  // 10: japanmesh.v1.MeshService.Neighbors:input_type -> japanmesh.v1.NeighborsRequest
+	11, // 11: japanmesh.v1.MeshService.Cover:input_type -> japanmesh.v1.CoverRequest
+	4,  // 12: japanmesh.v1.MeshService.Encode:output_type -> japanmesh.v1.EncodeResponse
+	4,  // 13: japanmesh.v1.MeshService.EncodeStream:output_type -> japanmesh.v1.EncodeResponse
+	6,  // 14: japanmesh.v1.MeshService.Decode:output_type -> japanmesh.v1.DecodeResponse
+	8,  // 15: japanmesh.v1.MeshService.Children:output_type -> japanmesh.v1.ChildrenResponse
+	10, // 16: japanmesh.v1.MeshService.Neighbors:output_type -> japanmesh.v1.NeighborsResponse
+	12, // 17: japanmesh.v1.MeshService.Cover:output_type -> japanmesh.v1.CoverResponse
+	12, // [12:18] is the sub-list for method output_type
+	6,  // [6:12] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_japanmeshpb_japanmesh_proto_init() }
+func file_japanmeshpb_japanmesh_proto_init() {
+	if File_japanmeshpb_japanmesh_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_japanmeshpb_japanmesh_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LatLng); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Bounds); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncodeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncodeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecodeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecodeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChildrenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChildrenResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NeighborsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NeighborsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CoverRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_japanmeshpb_japanmesh_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CoverResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_japanmeshpb_japanmesh_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_japanmeshpb_japanmesh_proto_goTypes,
+		DependencyIndexes: file_japanmeshpb_japanmesh_proto_depIdxs,
+		EnumInfos:         file_japanmeshpb_japanmesh_proto_enumTypes,
+		MessageInfos:      file_japanmeshpb_japanmesh_proto_msgTypes,
+	}.Build()
+	File_japanmeshpb_japanmesh_proto = out.File
+	file_japanmeshpb_japanmesh_proto_rawDesc = nil
+	file_japanmeshpb_japanmesh_proto_goTypes = nil
+	file_japanmeshpb_japanmesh_proto_depIdxs = nil
+}
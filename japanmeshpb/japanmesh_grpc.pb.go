@@ -0,0 +1,344 @@
+// 地域メッシュコードを変換・検索する gRPC サービス。
+// レベルは "1"、"2"、"3"、"1/2"、"1/4"、"1/8"、"2x"、"5x" のいずれかで、省略した場合は "3" とする。
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: japanmeshpb/japanmesh.proto
+
+package japanmeshpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	MeshService_Encode_FullMethodName       = "/japanmesh.v1.MeshService/Encode"
+	MeshService_EncodeStream_FullMethodName = "/japanmesh.v1.MeshService/EncodeStream"
+	MeshService_Decode_FullMethodName       = "/japanmesh.v1.MeshService/Decode"
+	MeshService_Children_FullMethodName     = "/japanmesh.v1.MeshService/Children"
+	MeshService_Neighbors_FullMethodName    = "/japanmesh.v1.MeshService/Neighbors"
+	MeshService_Cover_FullMethodName        = "/japanmesh.v1.MeshService/Cover"
+)
+
+// MeshServiceClient is the client API for MeshService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MeshServiceClient interface {
+	// Encode 緯度経度から地域メッシュコードを求める。
+	Encode(ctx context.Context, in *EncodeRequest, opts ...grpc.CallOption) (*EncodeResponse, error)
+	// EncodeStream 緯度経度を順に地域メッシュコードへ変換し、リクエストと同じ順序で返す。
+	// 変換できない緯度経度はストリームを中断せず、error を設定して返す。
+	EncodeStream(ctx context.Context, opts ...grpc.CallOption) (MeshService_EncodeStreamClient, error)
+	// Decode 地域メッシュコードの範囲と中心を求める。
+	Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*DecodeResponse, error)
+	// Children 1つ下のレベルの地域メッシュコードを求める。
+	Children(ctx context.Context, in *ChildrenRequest, opts ...grpc.CallOption) (*ChildrenResponse, error)
+	// Neighbors 隣接する同じレベルの地域メッシュコードを北から時計回りに求める。
+	Neighbors(ctx context.Context, in *NeighborsRequest, opts ...grpc.CallOption) (*NeighborsResponse, error)
+	// Cover 図形を覆う地域メッシュコードを求める。
+	Cover(ctx context.Context, in *CoverRequest, opts ...grpc.CallOption) (*CoverResponse, error)
+}
+
+type meshServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMeshServiceClient(cc grpc.ClientConnInterface) MeshServiceClient {
+	return &meshServiceClient{cc}
+}
+
+func (c *meshServiceClient) Encode(ctx context.Context, in *EncodeRequest, opts ...grpc.CallOption) (*EncodeResponse, error) {
+	out := new(EncodeResponse)
+	err := c.cc.Invoke(ctx, MeshService_Encode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) EncodeStream(ctx context.Context, opts ...grpc.CallOption) (MeshService_EncodeStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MeshService_ServiceDesc.Streams[0], MeshService_EncodeStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &meshServiceEncodeStreamClient{stream}
+	return x, nil
+}
+
+type MeshService_EncodeStreamClient interface {
+	Send(*EncodeRequest) error
+	Recv() (*EncodeResponse, error)
+	grpc.ClientStream
+}
+
+type meshServiceEncodeStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *meshServiceEncodeStreamClient) Send(m *EncodeRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *meshServiceEncodeStreamClient) Recv() (*EncodeResponse, error) {
+	m := new(EncodeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *meshServiceClient) Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*DecodeResponse, error) {
+	out := new(DecodeResponse)
+	err := c.cc.Invoke(ctx, MeshService_Decode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) Children(ctx context.Context, in *ChildrenRequest, opts ...grpc.CallOption) (*ChildrenResponse, error) {
+	out := new(ChildrenResponse)
+	err := c.cc.Invoke(ctx, MeshService_Children_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) Neighbors(ctx context.Context, in *NeighborsRequest, opts ...grpc.CallOption) (*NeighborsResponse, error) {
+	out := new(NeighborsResponse)
+	err := c.cc.Invoke(ctx, MeshService_Neighbors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) Cover(ctx context.Context, in *CoverRequest, opts ...grpc.CallOption) (*CoverResponse, error) {
+	out := new(CoverResponse)
+	err := c.cc.Invoke(ctx, MeshService_Cover_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MeshServiceServer is the server API for MeshService service.
+// All implementations must embed UnimplementedMeshServiceServer
+// for forward compatibility
+type MeshServiceServer interface {
+	// Encode 緯度経度から地域メッシュコードを求める。
+	Encode(context.Context, *EncodeRequest) (*EncodeResponse, error)
+	// EncodeStream 緯度経度を順に地域メッシュコードへ変換し、リクエストと同じ順序で返す。
+	// 変換できない緯度経度はストリームを中断せず、error を設定して返す。
+	EncodeStream(MeshService_EncodeStreamServer) error
+	// Decode 地域メッシュコードの範囲と中心を求める。
+	Decode(context.Context, *DecodeRequest) (*DecodeResponse, error)
+	// Children 1つ下のレベルの地域メッシュコードを求める。
+	Children(context.Context, *ChildrenRequest) (*ChildrenResponse, error)
+	// Neighbors 隣接する同じレベルの地域メッシュコードを北から時計回りに求める。
+	Neighbors(context.Context, *NeighborsRequest) (*NeighborsResponse, error)
+	// Cover 図形を覆う地域メッシュコードを求める。
+	Cover(context.Context, *CoverRequest) (*CoverResponse, error)
+	mustEmbedUnimplementedMeshServiceServer()
+}
+
+// UnimplementedMeshServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMeshServiceServer struct {
+}
+
+func (UnimplementedMeshServiceServer) Encode(context.Context, *EncodeRequest) (*EncodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Encode not implemented")
+}
+func (UnimplementedMeshServiceServer) EncodeStream(MeshService_EncodeStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method EncodeStream not implemented")
+}
+func (UnimplementedMeshServiceServer) Decode(context.Context, *DecodeRequest) (*DecodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decode not implemented")
+}
+func (UnimplementedMeshServiceServer) Children(context.Context, *ChildrenRequest) (*ChildrenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Children not implemented")
+}
+func (UnimplementedMeshServiceServer) Neighbors(context.Context, *NeighborsRequest) (*NeighborsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Neighbors not implemented")
+}
+func (UnimplementedMeshServiceServer) Cover(context.Context, *CoverRequest) (*CoverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cover not implemented")
+}
+func (UnimplementedMeshServiceServer) mustEmbedUnimplementedMeshServiceServer() {}
+
+// UnsafeMeshServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MeshServiceServer will
+// result in compilation errors.
+type UnsafeMeshServiceServer interface {
+	mustEmbedUnimplementedMeshServiceServer()
+}
+
+func RegisterMeshServiceServer(s grpc.ServiceRegistrar, srv MeshServiceServer) {
+	s.RegisterService(&MeshService_ServiceDesc, srv)
+}
+
+func _MeshService_Encode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EncodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).Encode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeshService_Encode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).Encode(ctx, req.(*EncodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_EncodeStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MeshServiceServer).EncodeStream(&meshServiceEncodeStreamServer{stream})
+}
+
+type MeshService_EncodeStreamServer interface {
+	Send(*EncodeResponse) error
+	Recv() (*EncodeRequest, error)
+	grpc.ServerStream
+}
+
+type meshServiceEncodeStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *meshServiceEncodeStreamServer) Send(m *EncodeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *meshServiceEncodeStreamServer) Recv() (*EncodeRequest, error) {
+	m := new(EncodeRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _MeshService_Decode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).Decode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeshService_Decode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).Decode(ctx, req.(*DecodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_Children_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChildrenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).Children(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeshService_Children_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).Children(ctx, req.(*ChildrenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_Neighbors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NeighborsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).Neighbors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeshService_Neighbors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).Neighbors(ctx, req.(*NeighborsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_Cover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).Cover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeshService_Cover_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).Cover(ctx, req.(*CoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MeshService_ServiceDesc is the grpc.ServiceDesc for MeshService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MeshService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "japanmesh.v1.MeshService",
+	HandlerType: (*MeshServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Encode",
+			Handler:    _MeshService_Encode_Handler,
+		},
+		{
+			MethodName: "Decode",
+			Handler:    _MeshService_Decode_Handler,
+		},
+		{
+			MethodName: "Children",
+			Handler:    _MeshService_Children_Handler,
+		},
+		{
+			MethodName: "Neighbors",
+			Handler:    _MeshService_Neighbors_Handler,
+		},
+		{
+			MethodName: "Cover",
+			Handler:    _MeshService_Cover_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EncodeStream",
+			Handler:       _MeshService_EncodeStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "japanmeshpb/japanmesh.proto",
+}
@@ -0,0 +1,68 @@
+// Command gen 陸域にかかる第3次地域区画のメッシュコードの一覧から、land パッケージに埋め込むビット列を作成する。
+//
+// 一覧は1行に1つのメッシュコード、または最初の列をメッシュコードとする CSV とし、見出しや空行は読み飛ばす。
+//
+//	go run ./land/internal/gen -version 2021 -o land/land.bin.gz codes.txt
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+	"github.com/keitaro1020/go-japanmesh/land"
+)
+
+func main() {
+	version := flag.String("version", "", "version of the land mesh data (e.g. 2021)")
+	out := flag.String("o", "land.bin.gz", "output file")
+	flag.Parse()
+	if *version == "" || flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: gen -version VERSION [-o FILE] FILE...")
+		os.Exit(2)
+	}
+	if err := run(*version, *out, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(version, out string, names []string) error {
+	var codes japanmesh.MeshCodes
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			field := strings.TrimSpace(strings.SplitN(s.Text(), ",", 2)[0])
+			code := japanmesh.MeshCode(strings.Trim(field, `"`))
+			if japanmesh.Validate(code) != nil {
+				// 見出しや空行
+				continue
+			}
+			codes = append(codes, code)
+		}
+		f.Close()
+		if err := s.Err(); err != nil {
+			return err
+		}
+	}
+	mask, err := land.NewMask(version, codes)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := mask.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
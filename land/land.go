@@ -0,0 +1,275 @@
+// Package land は第3次地域区画(基準地域メッシュ)が陸域にかかるかを判定する。
+//
+// 陸域にかかる第3次地域区画を、第1次地域区画ごとに 80×80 ビットのビット列で表し、
+// gzip で圧縮して land.bin.gz として埋め込む。ビット列は国土数値情報の土地利用3次メッシュなど、
+// 公開されている陸域のメッシュの一覧から次のコマンドで作成する。
+//
+//	go run ./land/internal/gen -version 2021 -o land/land.bin.gz codes.txt
+//
+// 現在は陸域のデータを同梱しておらず、land.bin.gz は見出しと空の版だけを持つ。
+// ビット列を作成して置き換えるまで、パッケージの関数は ErrMaskNotAvailable を返す。
+package land
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+	"strings"
+	"sync"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+)
+
+var (
+	ErrMaskNotAvailable = errors.New("land mask is not available")
+	ErrInvalidMask      = errors.New("invalid land mask")
+)
+
+//go:embed land.bin.gz
+var embeddedMask []byte
+
+// maskHeader ビット列の形式を表す1行目
+const maskHeader = "japanmesh-land\t1"
+
+// 第1次地域区画あたりの第3次地域区画の数(南北・東西)
+const blockSize = 80
+
+// blockBytes 第1次地域区画ごとのビット列の大きさ
+const blockBytes = blockSize * blockSize / 8
+
+// Mask 陸域にかかる第3次地域区画のビット列
+type Mask struct {
+	version string
+	// 第1次地域区画ごとのビット列。南から北、西から東の順に1区画1ビットとする
+	blocks map[japanmesh.MeshCode]*[blockBytes]byte
+}
+
+var (
+	defaultMask     *Mask
+	defaultMaskErr  error
+	defaultMaskOnce sync.Once
+)
+
+// Default 埋め込まれたビット列を返す。ビット列が埋め込まれていない場合は ErrMaskNotAvailable を返す。
+func Default() (*Mask, error) {
+	defaultMaskOnce.Do(func() {
+		defaultMask, defaultMaskErr = ReadMask(bytes.NewReader(embeddedMask))
+		if defaultMaskErr == nil && len(defaultMask.blocks) == 0 {
+			defaultMask, defaultMaskErr = nil, ErrMaskNotAvailable
+		}
+	})
+	return defaultMask, defaultMaskErr
+}
+
+// Version 埋め込まれたビット列の版。ビット列が埋め込まれていない場合は空文字を返す。
+func Version() string {
+	m, err := Default()
+	if err != nil {
+		return ""
+	}
+	return m.Version()
+}
+
+// IsLand 埋め込まれたビット列で、地域メッシュが陸域にかかるかを判定する。詳細は Mask.IsLand を参照。
+func IsLand(code japanmesh.MeshCode) (bool, error) {
+	m, err := Default()
+	if err != nil {
+		return false, err
+	}
+	return m.IsLand(code)
+}
+
+// IsLandAt 埋め込まれたビット列で、緯度経度が陸域にかかる第3次地域区画にあるかを判定する。詳細は Mask.IsLandAt を参照。
+func IsLandAt(geoCode japanmesh.GeoCode) (bool, error) {
+	m, err := Default()
+	if err != nil {
+		return false, err
+	}
+	return m.IsLandAt(geoCode)
+}
+
+// CoverageRatio 埋め込まれたビット列で、地域メッシュのうち陸域にかかる第3次地域区画の割合を求める。
+// 詳細は Mask.CoverageRatio を参照。
+func CoverageRatio(code japanmesh.MeshCode) (float64, error) {
+	m, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return m.CoverageRatio(code)
+}
+
+// NewMask 陸域にかかる第3次地域区画のメッシュコードからビット列を生成する。
+func NewMask(version string, codes japanmesh.MeshCodes) (*Mask, error) {
+	m := &Mask{version: version, blocks: map[japanmesh.MeshCode]*[blockBytes]byte{}}
+	for _, code := range codes {
+		if err := japanmesh.Validate(code); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, code)
+		}
+		if level, _ := japanmesh.GetLevel(code); level != japanmesh.Level3 {
+			return nil, fmt.Errorf("%w: %q", japanmesh.ErrInvalidLevel, code)
+		}
+		block, ok := m.blocks[code[:4]]
+		if !ok {
+			block = new([blockBytes]byte)
+			m.blocks[code[:4]] = block
+		}
+		i := bitIndex(code)
+		block[i/8] |= 1 << (i % 8)
+	}
+	return m, nil
+}
+
+// ReadMask Write で書き出したビット列を読み込む。
+func ReadMask(r io.Reader) (*Mask, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMask, err)
+	}
+	defer zr.Close()
+	br := bufio.NewReader(zr)
+	header, err := br.ReadString('\n')
+	if err != nil || strings.TrimSuffix(header, "\n") != maskHeader {
+		return nil, fmt.Errorf("%w: unknown format", ErrInvalidMask)
+	}
+	version, err := br.ReadString('\n')
+	if err != nil || !strings.HasPrefix(version, "version\t") {
+		return nil, fmt.Errorf("%w: missing version", ErrInvalidMask)
+	}
+	m := &Mask{version: strings.TrimSuffix(strings.TrimPrefix(version, "version\t"), "\n"), blocks: map[japanmesh.MeshCode]*[blockBytes]byte{}}
+	// 第1次地域区画のコード4桁と、ビット列の組が続く
+	for {
+		var code [4]byte
+		if _, err := io.ReadFull(br, code[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return m, nil
+			}
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMask, err)
+		}
+		if err := japanmesh.Validate(japanmesh.MeshCode(code[:])); err != nil {
+			return nil, fmt.Errorf("%w: %v: %q", ErrInvalidMask, err, code[:])
+		}
+		block := new([blockBytes]byte)
+		if _, err := io.ReadFull(br, block[:]); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMask, err)
+		}
+		m.blocks[japanmesh.MeshCode(code[:])] = block
+	}
+}
+
+// Write ビット列を埋め込み用の形式で書き出す。第1次地域区画はコードの昇順とする。
+func (m *Mask) Write(w io.Writer) error {
+	zw, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(zw, "%s\nversion\t%s\n", maskHeader, m.version); err != nil {
+		return err
+	}
+	codes := make(japanmesh.MeshCodes, 0, len(m.blocks))
+	for code := range m.blocks {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	for _, code := range codes {
+		if _, err := io.WriteString(zw, string(code)); err != nil {
+			return err
+		}
+		if _, err := zw.Write(m.blocks[code][:]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Version ビット列の版
+func (m *Mask) Version() string {
+	return m.version
+}
+
+// IsLand 地域メッシュが陸域にかかるかを判定する。
+//
+// 第3次地域区画より下位のメッシュは、そのメッシュを含む第3次地域区画で判定するため、
+// 海岸線付近では海域のメッシュも陸域と判定する。
+// 第1次・第2次地域区画は、区画内の第3次地域区画のいずれかが陸域にかかる場合に陸域とする。
+// 統合地域メッシュは ErrInvalidLevel を返す。
+func (m *Mask) IsLand(code japanmesh.MeshCode) (bool, error) {
+	land, _, err := m.count(code)
+	return land > 0, err
+}
+
+// IsLandAt 緯度経度を含む第3次地域区画が陸域にかかるかを判定する。日本の国土の範囲外は陸域ではないとする。
+func (m *Mask) IsLandAt(geoCode japanmesh.GeoCode) (bool, error) {
+	code, err := japanmesh.ToCode(geoCode, japanmesh.Level3)
+	if errors.Is(err, japanmesh.ErrInvalidArea) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return m.IsLand(code)
+}
+
+// CoverageRatio 地域メッシュ内の第3次地域区画のうち、陸域にかかる区画の割合を求める。
+// 第3次地域区画とその下位のメッシュは0または1とする。統合地域メッシュは ErrInvalidLevel を返す。
+func (m *Mask) CoverageRatio(code japanmesh.MeshCode) (float64, error) {
+	land, total, err := m.count(code)
+	if err != nil {
+		return 0, err
+	}
+	return float64(land) / float64(total), nil
+}
+
+// count 地域メッシュ内の陸域にかかる第3次地域区画の数と、第3次地域区画の数を求める。
+func (m *Mask) count(code japanmesh.MeshCode) (int, int, error) {
+	if err := japanmesh.Validate(code); err != nil {
+		return 0, 0, err
+	}
+	level, err := japanmesh.GetLevel(code)
+	if err != nil {
+		return 0, 0, err
+	}
+	block := m.blocks[code[:4]]
+	switch level {
+	case japanmesh.Level1:
+		var land int
+		if block != nil {
+			for _, b := range block {
+				land += bits.OnesCount8(b)
+			}
+		}
+		return land, blockSize * blockSize, nil
+	case japanmesh.Level2:
+		var land int
+		if block != nil {
+			// 第2次地域区画の南西端の第3次地域区画から、10×10 の区画のビットを数える
+			y2, x2 := int(code[4]-'0')*10, int(code[5]-'0')*10
+			for y := y2; y < y2+10; y++ {
+				for x := x2; x < x2+10; x++ {
+					i := y*blockSize + x
+					land += int(block[i/8] >> (i % 8) & 1)
+				}
+			}
+		}
+		return land, 100, nil
+	case japanmesh.Level3, japanmesh.LevelHalf, japanmesh.LevelQuarter, japanmesh.LevelOneEighth:
+		if block == nil {
+			return 0, 1, nil
+		}
+		i := bitIndex(code[:8])
+		return int(block[i/8] >> (i % 8) & 1), 1, nil
+	}
+	return 0, 0, japanmesh.ErrInvalidLevel
+}
+
+// bitIndex 第3次地域区画の、第1次地域区画内のビットの位置
+func bitIndex(code japanmesh.MeshCode) int {
+	y := int(code[4]-'0')*10 + int(code[6]-'0')
+	x := int(code[5]-'0')*10 + int(code[7]-'0')
+	return y*blockSize + x
+}
@@ -0,0 +1,186 @@
+package land
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"reflect"
+	"testing"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+)
+
+// テスト用のビット列。533945 の区画内の2区画と、別の第1次地域区画の1区画を陸域とする
+func newTestMask(t *testing.T) *Mask {
+	t.Helper()
+	m, err := NewMask("test", japanmesh.MeshCodes{"53394547", "53394500", "53394547", "64414277"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMask_IsLand(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      japanmesh.MeshCode
+		want      bool
+		wantRatio float64
+		wantErr   error
+	}{
+		{name: "level3 land", code: "53394547", want: true, wantRatio: 1},
+		{name: "level3 sea", code: "53394548", want: false, wantRatio: 0},
+		{name: "level3 in another level1", code: "53404547", want: false, wantRatio: 0},
+		{name: "half", code: "533945471", want: true, wantRatio: 1},
+		{name: "one eighth", code: "53394500444", want: true, wantRatio: 1},
+		{name: "level2", code: "533945", want: true, wantRatio: 0.02},
+		{name: "level2 sea", code: "533946", want: false, wantRatio: 0},
+		{name: "level1", code: "6441", want: true, wantRatio: 1.0 / 6400},
+		{name: "twofold", code: "533945465", wantErr: japanmesh.ErrInvalidLevel},
+		{name: "invalid", code: "53394", wantErr: japanmesh.ErrInvalidMeshCode},
+	}
+	m := newTestMask(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.IsLand(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IsLand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IsLand() = %v, want %v", got, tt.want)
+			}
+			ratio, err := m.CoverageRatio(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CoverageRatio() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ratio != tt.wantRatio {
+				t.Errorf("CoverageRatio() = %v, want %v", ratio, tt.wantRatio)
+			}
+		})
+	}
+}
+
+func TestMask_IsLandAt(t *testing.T) {
+	tests := []struct {
+		name string
+		geo  japanmesh.GeoCode
+		want bool
+	}{
+		{name: "land", geo: japanmesh.GeoCode{Latitude: 35.70078, Longitude: 139.71475}, want: true},
+		{name: "sea", geo: japanmesh.GeoCode{Latitude: 35.70078, Longitude: 139.73}, want: false},
+		{name: "out of area", geo: japanmesh.GeoCode{Latitude: 10, Longitude: 100}, want: false},
+	}
+	m := newTestMask(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.IsLandAt(tt.geo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("IsLandAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMask_Write(t *testing.T) {
+	m := newTestMask(t)
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadMask(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("ReadMask() = %+v, want %+v", got, m)
+	}
+
+	// 途中で切れたビット列
+	raw := gunzip(t, buf.Bytes())
+	for _, data := range [][]byte{
+		gzipBytes(t, raw[:len(raw)-1]),
+		gzipBytes(t, []byte("unknown\n")),
+		gzipBytes(t, []byte(maskHeader+"\n")),
+		gzipBytes(t, append([]byte(maskHeader+"\nversion\t\n0000"), make([]byte, blockBytes)...)),
+		[]byte("not gzip"),
+	} {
+		if _, err := ReadMask(bytes.NewReader(data)); !errors.Is(err, ErrInvalidMask) {
+			t.Errorf("ReadMask() error = %v, want %v", err, ErrInvalidMask)
+		}
+	}
+}
+
+func TestNewMask_Errors(t *testing.T) {
+	if _, err := NewMask("test", japanmesh.MeshCodes{"533945"}); !errors.Is(err, japanmesh.ErrInvalidLevel) {
+		t.Errorf("NewMask() error = %v, want %v", err, japanmesh.ErrInvalidLevel)
+	}
+	if _, err := NewMask("test", japanmesh.MeshCodes{"53394"}); !errors.Is(err, japanmesh.ErrInvalidMeshCode) {
+		t.Errorf("NewMask() error = %v, want %v", err, japanmesh.ErrInvalidMeshCode)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if Version() == "" {
+		// ビット列を埋め込まずに配布している場合
+		if _, err := IsLand("53394525"); !errors.Is(err, ErrMaskNotAvailable) {
+			t.Errorf("IsLand() error = %v, want %v", err, ErrMaskNotAvailable)
+		}
+		if _, err := IsLandAt(japanmesh.GeoCode{Latitude: 35.68944, Longitude: 139.69167}); !errors.Is(err, ErrMaskNotAvailable) {
+			t.Errorf("IsLandAt() error = %v, want %v", err, ErrMaskNotAvailable)
+		}
+		if _, err := CoverageRatio("5339"); !errors.Is(err, ErrMaskNotAvailable) {
+			t.Errorf("CoverageRatio() error = %v, want %v", err, ErrMaskNotAvailable)
+		}
+		// 陸域のデータを同梱するまで、以降の実データでの確認は行わない
+		t.Skip("land.bin.gz is an empty placeholder; real-data checks are blocked until the bitset is generated with go run ./land/internal/gen")
+	}
+	tests := []struct {
+		name string
+		code japanmesh.MeshCode
+		want bool
+	}{
+		// 東京都庁の位置する第3次地域区画
+		{name: "land", code: "53394525", want: true},
+		// 九十九里浜の沖の太平洋。第1次地域区画 5340 は房総半島の陸域を含む
+		{name: "sea", code: "53401742", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := IsLand(tt.code); err != nil || got != tt.want {
+				t.Errorf("IsLand(%v) = %v, %v, want %v", tt.code, got, err, tt.want)
+			}
+		})
+	}
+	if got, err := CoverageRatio("5340"); err != nil || got <= 0 || got >= 1 {
+		t.Errorf("CoverageRatio(5340) = %v, %v, want between 0 and 1", got, err)
+	}
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(zr); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,288 @@
+package japanmesh
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GeoCodeArray GeoJSON と同じ [経度, 緯度] の配列として JSON に変換する GeoCode。
+// JSON から変換する際は GeoCode と同様にいずれの形式も受け付ける。
+type GeoCodeArray GeoCode
+
+// GeoCodeWKT WKT の "POINT(経度 緯度)" 形式の文字列として JSON に変換する GeoCode。
+// JSON から変換する際は GeoCode と同様にいずれの形式も受け付ける。
+type GeoCodeWKT GeoCode
+
+// MarshalText encoding.TextMarshaler の実装
+func (code MeshCode) MarshalText() ([]byte, error) {
+	return []byte(code), nil
+}
+
+// UnmarshalText encoding.TextUnmarshaler の実装。規格に沿わないメッシュコードはエラーとする。
+func (code *MeshCode) UnmarshalText(text []byte) error {
+	c := MeshCode(text)
+	if err := Validate(c); err != nil {
+		return err
+	}
+	*code = c
+	return nil
+}
+
+// MarshalJSON json.Marshaler の実装
+func (code MeshCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(code))
+}
+
+// UnmarshalJSON json.Unmarshaler の実装。文字列の他に数値のメッシュコードも受け付ける。
+func (code *MeshCode) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return code.UnmarshalText([]byte(s))
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	return code.UnmarshalText([]byte(n.String()))
+}
+
+// Scan sql.Scanner の実装。NULL は空のメッシュコードとする。
+func (code *MeshCode) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*code = ""
+		return nil
+	case string:
+		return code.UnmarshalText([]byte(v))
+	case []byte:
+		return code.UnmarshalText(v)
+	case int64:
+		return code.UnmarshalText([]byte(strconv.FormatInt(v, 10)))
+	}
+	return fmt.Errorf("japanmesh: cannot scan %T into MeshCode", src)
+}
+
+// Value driver.Valuer の実装。空のメッシュコードは NULL とする。
+func (code MeshCode) Value() (driver.Value, error) {
+	if code == "" {
+		return nil, nil
+	}
+	if err := Validate(code); err != nil {
+		return nil, err
+	}
+	return string(code), nil
+}
+
+type geoCodeObject struct {
+	Latitude  *float64 `json:"lat"`
+	Longitude *float64 `json:"lng"`
+}
+
+// MarshalText encoding.TextMarshaler の実装。WKT の POINT 形式に変換する。
+func (geoCode GeoCode) MarshalText() ([]byte, error) {
+	return []byte(geoCode.wkt()), nil
+}
+
+// UnmarshalText encoding.TextUnmarshaler の実装。WKT(EWKT) の POINT 形式を受け付ける。
+func (geoCode *GeoCode) UnmarshalText(text []byte) error {
+	g, err := parseWKTPoint(string(text))
+	if err != nil {
+		return err
+	}
+	*geoCode = g
+	return nil
+}
+
+// MarshalJSON json.Marshaler の実装。{"lat":..,"lng":..} の形式とする。
+// 配列や WKT の形式には GeoCodeArray、GeoCodeWKT を使う。
+func (geoCode GeoCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(geoCodeObject{Latitude: &geoCode.Latitude, Longitude: &geoCode.Longitude})
+}
+
+// UnmarshalJSON json.Unmarshaler の実装。オブジェクト、配列、WKT 文字列のいずれの形式も受け付ける。
+func (geoCode *GeoCode) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return ErrInvalidGeoCode
+	}
+	var g GeoCode
+	switch data[0] {
+	case 'n':
+		return nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return geoCode.UnmarshalText([]byte(s))
+	case '[':
+		var a []float64
+		if err := json.Unmarshal(data, &a); err != nil {
+			return err
+		}
+		if len(a) != 2 {
+			return ErrInvalidGeoCode
+		}
+		g = GeoCode{Latitude: a[1], Longitude: a[0]}
+	default:
+		var o geoCodeObject
+		if err := json.Unmarshal(data, &o); err != nil {
+			return err
+		}
+		if o.Latitude == nil || o.Longitude == nil {
+			return ErrInvalidGeoCode
+		}
+		g = GeoCode{Latitude: *o.Latitude, Longitude: *o.Longitude}
+	}
+	if !g.isValid() {
+		return ErrInvalidGeoCode
+	}
+	*geoCode = g
+	return nil
+}
+
+// MarshalJSON json.Marshaler の実装。[経度, 緯度] の形式とする。
+func (geoCode GeoCodeArray) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]float64{geoCode.Longitude, geoCode.Latitude})
+}
+
+// UnmarshalJSON json.Unmarshaler の実装。オブジェクト、配列、WKT 文字列のいずれの形式も受け付ける。
+func (geoCode *GeoCodeArray) UnmarshalJSON(data []byte) error {
+	return (*GeoCode)(geoCode).UnmarshalJSON(data)
+}
+
+// MarshalJSON json.Marshaler の実装。WKT の POINT 形式の文字列とする。
+func (geoCode GeoCodeWKT) MarshalJSON() ([]byte, error) {
+	return json.Marshal(GeoCode(geoCode).wkt())
+}
+
+// UnmarshalJSON json.Unmarshaler の実装。オブジェクト、配列、WKT 文字列のいずれの形式も受け付ける。
+func (geoCode *GeoCodeWKT) UnmarshalJSON(data []byte) error {
+	return (*GeoCode)(geoCode).UnmarshalJSON(data)
+}
+
+// Scan sql.Scanner の実装。WKT(EWKT) の文字列と、PostGIS が返す WKB(EWKB) を受け付ける。
+func (geoCode *GeoCode) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*geoCode = GeoCode{}
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("japanmesh: cannot scan %T into GeoCode", src)
+	}
+
+	if g, err := parseWKBPoint(b); err == nil {
+		*geoCode = g
+		return nil
+	}
+	if raw, err := hex.DecodeString(string(b)); err == nil {
+		if g, err := parseWKBPoint(raw); err == nil {
+			*geoCode = g
+			return nil
+		}
+	}
+	return geoCode.UnmarshalText(b)
+}
+
+// Value driver.Valuer の実装。WKT の POINT 形式の文字列とする。
+func (geoCode GeoCode) Value() (driver.Value, error) {
+	if !geoCode.isValid() {
+		return nil, ErrInvalidGeoCode
+	}
+	return geoCode.wkt(), nil
+}
+
+func (geoCode GeoCode) isValid() bool {
+	return geoCode.Latitude >= -90 && geoCode.Latitude <= 90 &&
+		geoCode.Longitude >= -180 && geoCode.Longitude <= 180
+}
+
+func (geoCode GeoCode) wkt() string {
+	return "POINT(" +
+		strconv.FormatFloat(geoCode.Longitude, 'f', -1, 64) + " " +
+		strconv.FormatFloat(geoCode.Latitude, 'f', -1, 64) + ")"
+}
+
+// parseWKTPoint "POINT(lng lat)" または "SRID=6668;POINT(lng lat)" を解析する。
+func parseWKTPoint(s string) (GeoCode, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ';'); i >= 0 && strings.HasPrefix(strings.ToUpper(s), "SRID=") {
+		s = strings.TrimSpace(s[i+1:])
+	}
+	if !strings.HasPrefix(strings.ToUpper(s), "POINT") {
+		return GeoCode{}, ErrInvalidGeoCode
+	}
+	s = strings.TrimSpace(s[len("POINT"):])
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return GeoCode{}, ErrInvalidGeoCode
+	}
+	fields := strings.Fields(s[1 : len(s)-1])
+	if len(fields) != 2 {
+		return GeoCode{}, ErrInvalidGeoCode
+	}
+	lng, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return GeoCode{}, ErrInvalidGeoCode
+	}
+	lat, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return GeoCode{}, ErrInvalidGeoCode
+	}
+	g := GeoCode{Latitude: lat, Longitude: lng}
+	if !g.isValid() {
+		return GeoCode{}, ErrInvalidGeoCode
+	}
+	return g, nil
+}
+
+// parseWKBPoint WKB(EWKB) の Point を解析する。
+func parseWKBPoint(b []byte) (GeoCode, error) {
+	if len(b) < 21 {
+		return GeoCode{}, ErrInvalidGeoCode
+	}
+	var order binary.ByteOrder
+	switch b[0] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return GeoCode{}, ErrInvalidGeoCode
+	}
+	typ := order.Uint32(b[1:5])
+	offset := 5
+	if typ&0x20000000 != 0 {
+		// EWKB の SRID
+		offset += 4
+	}
+	if typ&0xffff != 1 || len(b) != offset+16 {
+		return GeoCode{}, ErrInvalidGeoCode
+	}
+	g := GeoCode{
+		Longitude: math.Float64frombits(order.Uint64(b[offset : offset+8])),
+		Latitude:  math.Float64frombits(order.Uint64(b[offset+8 : offset+16])),
+	}
+	if !g.isValid() {
+		return GeoCode{}, ErrInvalidGeoCode
+	}
+	return g, nil
+}
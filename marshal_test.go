@@ -0,0 +1,222 @@
+package japanmesh
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMeshCode_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    MeshCode
+		wantErr bool
+	}{
+		{name: "string", data: `"53394547"`, want: "53394547", wantErr: false},
+		{name: "number", data: `53394547`, want: "53394547", wantErr: false},
+		{name: "null", data: `null`, want: "", wantErr: false},
+		{name: "fivefold", data: `"5339454"`, want: "5339454", wantErr: false},
+		{name: "invalid digit", data: `"53394"`, want: "", wantErr: true},
+		{name: "invalid area", data: `"00000000"`, want: "", wantErr: true},
+		{name: "not number", data: `"5339454a"`, want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got MeshCode
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeshCode_JSONRoundTrip(t *testing.T) {
+	type row struct {
+		Code  MeshCode            `json:"code"`
+		Codes map[MeshCode]string `json:"codes"`
+	}
+	in := row{Code: "533945471", Codes: map[MeshCode]string{"5339": "tokyo"}}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"code":"533945471","codes":{"5339":"tokyo"}}`; string(b) != want {
+		t.Errorf("MarshalJSON() got = %s, want %s", b, want)
+	}
+	var out row
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("UnmarshalJSON() got = %v, want %v", out, in)
+	}
+}
+
+func TestMeshCode_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    MeshCode
+		wantErr bool
+	}{
+		{name: "string", src: "53394547", want: "53394547", wantErr: false},
+		{name: "bytes", src: []byte("53394547"), want: "53394547", wantErr: false},
+		{name: "int64", src: int64(53394547), want: "53394547", wantErr: false},
+		{name: "null", src: nil, want: "", wantErr: false},
+		{name: "invalid", src: "1", want: "", wantErr: true},
+		{name: "unsupported", src: 1.5, want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got MeshCode
+			err := got.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Scan() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeshCode_Value(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    MeshCode
+		want    driver.Value
+		wantErr bool
+	}{
+		{name: "valid", code: "53394547", want: "53394547", wantErr: false},
+		{name: "empty", code: "", want: nil, wantErr: false},
+		{name: "invalid", code: "1", want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.code.Value()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Value() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Value() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeoCode_MarshalJSON(t *testing.T) {
+	geoCode := GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "object", value: geoCode, want: `{"lat":35.70078,"lng":139.71475}`},
+		{name: "array", value: GeoCodeArray(geoCode), want: `[139.71475,35.70078]`},
+		{name: "wkt", value: GeoCodeWKT(geoCode), want: `"POINT(139.71475 35.70078)"`},
+		{name: "struct field", value: struct {
+			A GeoCodeArray `json:"a"`
+			W *GeoCodeWKT  `json:"w"`
+		}{A: GeoCodeArray(geoCode), W: (*GeoCodeWKT)(&geoCode)}, want: `{"a":[139.71475,35.70078],"w":"POINT(139.71475 35.70078)"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Errorf("MarshalJSON() error = %v", err)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeoCodeWrapper_UnmarshalJSON(t *testing.T) {
+	want := GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	for _, data := range []string{`[139.71475,35.70078]`, `"POINT(139.71475 35.70078)"`, `{"lat":35.70078,"lng":139.71475}`} {
+		var a GeoCodeArray
+		if err := json.Unmarshal([]byte(data), &a); err != nil || GeoCode(a) != want {
+			t.Errorf("GeoCodeArray.UnmarshalJSON(%s) = %v, %v", data, a, err)
+		}
+		var w GeoCodeWKT
+		if err := json.Unmarshal([]byte(data), &w); err != nil || GeoCode(w) != want {
+			t.Errorf("GeoCodeWKT.UnmarshalJSON(%s) = %v, %v", data, w, err)
+		}
+	}
+	var a GeoCodeArray
+	if err := json.Unmarshal([]byte(`[139.71475]`), &a); err != ErrInvalidGeoCode {
+		t.Errorf("GeoCodeArray.UnmarshalJSON() error = %v, want %v", err, ErrInvalidGeoCode)
+	}
+}
+
+func TestGeoCode_UnmarshalJSON(t *testing.T) {
+	want := GeoCode{Latitude: 35.70078, Longitude: 139.71475}
+	tests := []struct {
+		name    string
+		data    string
+		want    GeoCode
+		wantErr bool
+	}{
+		{name: "object", data: `{"lat":35.70078,"lng":139.71475}`, want: want, wantErr: false},
+		{name: "array", data: `[139.71475,35.70078]`, want: want, wantErr: false},
+		{name: "wkt", data: `"POINT(139.71475 35.70078)"`, want: want, wantErr: false},
+		{name: "ewkt", data: `"SRID=6668;POINT(139.71475 35.70078)"`, want: want, wantErr: false},
+		{name: "missing lng", data: `{"lat":35.70078}`, want: GeoCode{}, wantErr: true},
+		{name: "short array", data: `[139.71475]`, want: GeoCode{}, wantErr: true},
+		{name: "out of range", data: `[35.70078,139.71475]`, want: GeoCode{}, wantErr: true},
+		{name: "not point", data: `"LINESTRING(139 35, 140 36)"`, want: GeoCode{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got GeoCode
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeoCode_Scan(t *testing.T) {
+	want := GeoCode{Latitude: 35.5, Longitude: 139.75}
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    GeoCode
+		wantErr bool
+	}{
+		{name: "wkt", src: "POINT(139.75 35.5)", want: want, wantErr: false},
+		// SELECT 'SRID=6668;POINT(139.75 35.5)'::geometry
+		{name: "ewkb hex", src: []byte("01010000200C1A000000000000007861400000000000C04140"), want: want, wantErr: false},
+		{name: "null", src: nil, want: GeoCode{}, wantErr: false},
+		{name: "invalid", src: "POINT(139.75)", want: GeoCode{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got GeoCode
+			err := got.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Scan() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
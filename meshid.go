@@ -0,0 +1,180 @@
+package japanmesh
+
+import "strconv"
+
+// MeshID 地域メッシュコードを整数で表したもの。
+//
+// 下位4ビットにレベル、それより上位のビットに11桁に満たない桁を0で埋めたメッシュコードを持つ。
+// 2分の1地域メッシュ以下の桁は 1〜4 のため、0で埋めた上位のレベルのメッシュは配下のメッシュより小さくなり、
+// MeshID の大小関係は階層の順序(親、子孫の順)を保つ。
+// ただし統合地域メッシュは階層に含まれないため、第2次地域区画の配下で他のメッシュと混在した順序になる。
+type MeshID uint64
+
+// meshIDLevelBits MeshID のレベルに割り当てるビット数
+const meshIDLevelBits = 4
+
+// meshIDLevels MeshID に格納するレベルの番号(0 は不正な値)
+var meshIDLevels = [...]Level{
+	"",
+	Level1,
+	Level2,
+	Level3,
+	LevelHalf,
+	LevelQuarter,
+	LevelOneEighth,
+	LevelFivefold,
+	LevelTwofold,
+}
+
+// pow10 10のべき乗
+var pow10 = [...]uint64{
+	1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000, 1000000000, 10000000000, 100000000000,
+}
+
+// NewMeshID 地域メッシュコードを MeshID に変換する。
+func NewMeshID(code MeshCode) (MeshID, error) {
+	if err := Validate(code); err != nil {
+		return 0, err
+	}
+	level, err := GetLevel(code)
+	if err != nil {
+		return 0, err
+	}
+	var digits [maxCodeDigit]byte
+	n := copy(digits[:], code)
+	return packMeshID(digits[:n], level), nil
+}
+
+// ToMeshID 緯度経度から MeshID を取得する。メモリを確保せずに変換する。
+func ToMeshID(geoCode GeoCode, level Level) (MeshID, error) {
+	var buf [maxCodeDigit]byte
+	digits, err := AppendCode(buf[:0], geoCode, level)
+	if err != nil {
+		return 0, err
+	}
+	return packMeshID(digits, level), nil
+}
+
+// GetParentID 指定した MeshID の直上のレベルの MeshID を取得する。
+func GetParentID(id MeshID) (MeshID, error) {
+	c, err := id.toCell()
+	if err != nil {
+		return 0, err
+	}
+	parent, ok := c.parent()
+	if !ok {
+		return 0, ErrInvalidLevel
+	}
+	return parent.toMeshID()
+}
+
+// GetNeighborIDs 指定した MeshID に隣接する、同じレベルの MeshID を北から時計回りに取得する。
+func GetNeighborIDs(id MeshID) ([]MeshID, error) {
+	c, err := id.toCell()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]MeshID, 0, len(neighborOffsets))
+	for _, offset := range neighborOffsets {
+		neighbor, err := cell{level: c.level, y: c.y + offset[0], x: c.x + offset[1]}.toMeshID()
+		if err != nil {
+			continue
+		}
+		ids = append(ids, neighbor)
+	}
+	return ids, nil
+}
+
+// Level MeshID のレベルを取得する。
+func (id MeshID) Level() Level {
+	index := int(id & (1<<meshIDLevelBits - 1))
+	if index >= len(meshIDLevels) {
+		return ""
+	}
+	return meshIDLevels[index]
+}
+
+// MeshCode MeshID を地域メッシュコードに変換する。不正な MeshID の場合は空文字を返す。
+func (id MeshID) MeshCode() MeshCode {
+	var buf [maxCodeDigit]byte
+	digits, ok := id.appendDigits(buf[:0])
+	if !ok {
+		return ""
+	}
+	return MeshCode(digits)
+}
+
+// String fmt.Stringer の実装
+func (id MeshID) String() string {
+	if code := id.MeshCode(); code != "" {
+		return string(code)
+	}
+	return "MeshID(" + strconv.FormatUint(uint64(id), 10) + ")"
+}
+
+func newMeshID(padded uint64, level Level) MeshID {
+	return MeshID(padded<<meshIDLevelBits | uint64(getMeshIDLevelIndex(level)))
+}
+
+// getMeshIDLevelIndex meshIDLevels におけるレベルの位置を取得する(不明なレベルは0)。
+func getMeshIDLevelIndex(level Level) int {
+	for i, l := range meshIDLevels {
+		if l == level {
+			return i
+		}
+	}
+	return 0
+}
+
+// appendDigits MeshID のメッシュコードの各桁を dst に追加する。
+func (id MeshID) appendDigits(dst []byte) ([]byte, bool) {
+	mesh, ok := getMesh(id.Level())
+	if !ok {
+		return dst, false
+	}
+	padded := uint64(id) >> meshIDLevelBits
+	if padded >= pow10[maxCodeDigit] {
+		return dst, false
+	}
+	for i := 0; i < maxCodeDigit; i++ {
+		digit := padded / pow10[maxCodeDigit-1-i] % 10
+		if i < mesh.Digit {
+			dst = append(dst, byte('0'+digit))
+		} else if digit != 0 {
+			// レベルより下位の桁は0で埋められている
+			return dst, false
+		}
+	}
+	return dst, true
+}
+
+func (id MeshID) toCell() (cell, error) {
+	var buf [maxCodeDigit]byte
+	digits, ok := id.appendDigits(buf[:0])
+	if !ok {
+		return cell{}, ErrInvalidMeshCode
+	}
+	// 各桁の範囲を検証する
+	if err := validateDigits(digits); err != nil {
+		return cell{}, err
+	}
+	return parseCell(digits), nil
+}
+
+func (c cell) toMeshID() (MeshID, error) {
+	var buf [maxCodeDigit]byte
+	digits, err := formatCell(buf[:0], c)
+	if err != nil {
+		return 0, err
+	}
+	return packMeshID(digits, c.level), nil
+}
+
+// packMeshID 検証済みのメッシュコードの各桁を MeshID に変換する。
+func packMeshID(digits []byte, level Level) MeshID {
+	var padded uint64
+	for _, d := range digits {
+		padded = padded*10 + uint64(d-'0')
+	}
+	return newMeshID(padded*pow10[maxCodeDigit-len(digits)], level)
+}
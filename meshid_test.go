@@ -0,0 +1,142 @@
+package japanmesh
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNewMeshID(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    MeshCode
+		want    MeshID
+		wantErr bool
+	}{
+		{name: "level1", code: "5339", want: 53390000000<<4 | 1, wantErr: false},
+		{name: "level2", code: "533945", want: 53394500000<<4 | 2, wantErr: false},
+		{name: "level3", code: "53394547", want: 53394547000<<4 | 3, wantErr: false},
+		{name: "level1-2", code: "533945471", want: 53394547100<<4 | 4, wantErr: false},
+		{name: "level1-4", code: "5339454711", want: 53394547110<<4 | 5, wantErr: false},
+		{name: "level1-8", code: "53394547112", want: 53394547112<<4 | 6, wantErr: false},
+		{name: "invalid", code: "1", want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMeshID(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewMeshID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NewMeshID() got = %v, want %v", uint64(got), uint64(tt.want))
+			}
+			if tt.wantErr {
+				return
+			}
+			if code := got.MeshCode(); code != tt.code {
+				t.Errorf("MeshID.MeshCode() got = %v, want %v", code, tt.code)
+			}
+			if level, _ := GetLevel(tt.code); got.Level() != level {
+				t.Errorf("MeshID.Level() got = %v, want %v", got.Level(), level)
+			}
+		})
+	}
+}
+
+func TestMeshID_MeshCode(t *testing.T) {
+	tests := []struct {
+		name string
+		id   MeshID
+		want MeshCode
+	}{
+		{name: "zero", id: 0, want: ""},
+		{name: "unknown level", id: 53390000000<<4 | 15, want: ""},
+		{name: "not padded", id: 53394547000<<4 | 1, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.MeshCode(); got != tt.want {
+				t.Errorf("MeshID.MeshCode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeshID_Order(t *testing.T) {
+	codes := MeshCodes{
+		"53394547112", "5340", "533945", "53394547", "533945471", "5339", "5339454711", "533946", "53394548", "533945472",
+	}
+	want := MeshCodes{
+		"5339", "533945", "53394547", "533945471", "5339454711", "53394547112", "533945472", "53394548", "533946", "5340",
+	}
+	ids := make([]MeshID, len(codes))
+	for i, code := range codes {
+		id, err := NewMeshID(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = id
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	got := make(MeshCodes, len(ids))
+	for i, id := range ids {
+		got[i] = id.MeshCode()
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MeshID order got = %v, want %v", got, want)
+	}
+}
+
+func TestToMeshID(t *testing.T) {
+	got, err := ToMeshID(GeoCode{Latitude: 35.70078, Longitude: 139.71475}, Level3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MeshCode() != "53394547" {
+		t.Errorf("ToMeshID() got = %v, want %v", got, "53394547")
+	}
+	if _, err := ToMeshID(GeoCode{}, Level3); err != ErrInvalidArea {
+		t.Errorf("ToMeshID() error = %v, wantErr %v", err, ErrInvalidArea)
+	}
+}
+
+func TestGetParentID(t *testing.T) {
+	for _, code := range []MeshCode{"533945", "53394547", "533945471", "5339454711", "53394547112"} {
+		t.Run(string(code), func(t *testing.T) {
+			id, _ := NewMeshID(code)
+			got, err := GetParentID(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := GetParent(code)
+			if got.MeshCode() != want {
+				t.Errorf("GetParentID() got = %v, want %v", got, want)
+			}
+		})
+	}
+	id, _ := NewMeshID("5339")
+	if _, err := GetParentID(id); err != ErrInvalidLevel {
+		t.Errorf("GetParentID() error = %v, wantErr %v", err, ErrInvalidLevel)
+	}
+}
+
+func TestGetNeighborIDs(t *testing.T) {
+	for _, code := range []MeshCode{"533977", "53394547", "533945474"} {
+		t.Run(string(code), func(t *testing.T) {
+			id, _ := NewMeshID(code)
+			ids, err := GetNeighborIDs(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(MeshCodes, len(ids))
+			for i, id := range ids {
+				got[i] = id.MeshCode()
+			}
+			want, _ := GetNeighbors(code)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GetNeighborIDs() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
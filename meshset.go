@@ -0,0 +1,298 @@
+package japanmesh
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+// meshSetVersion MeshSet のバイト列の形式のバージョン
+const meshSetVersion = 1
+
+var ErrInvalidMeshSet = errors.New("invalid meshset")
+
+// MeshSet 地域メッシュコードの集合。
+//
+// レベルごとに、メッシュを南から北、西から東の順に並べた通し番号の連続する範囲として保持するため、
+// 面的に広がる大量のメッシュを少ないメモリで扱える。
+// 異なるレベルのメッシュは別の要素として扱う(上位のメッシュは配下のメッシュを含まない)。
+// MeshSet は並行して使用できない。
+type MeshSet struct {
+	levels [len(meshIDLevels)]rangeSet
+}
+
+// meshRange 通し番号の範囲 [start, end)
+type meshRange struct {
+	start uint64
+	end   uint64
+}
+
+type rangeSet struct {
+	ranges []meshRange
+	// まだ ranges に反映していない追加された通し番号
+	pending []uint64
+}
+
+// NewMeshSet 地域メッシュコードの集合を生成する。
+func NewMeshSet(codes ...MeshCode) (*MeshSet, error) {
+	s := &MeshSet{}
+	for _, code := range codes {
+		if err := s.Add(code); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Add 地域メッシュコードを追加する。
+func (s *MeshSet) Add(code MeshCode) error {
+	c, err := toCell(code)
+	if err != nil {
+		return err
+	}
+	rs := &s.levels[getMeshIDLevelIndex(c.level)]
+	rs.pending = append(rs.pending, c.index())
+	return nil
+}
+
+// Contains 地域メッシュコードが含まれるかを判定する。
+func (s *MeshSet) Contains(code MeshCode) bool {
+	c, err := toCell(code)
+	if err != nil {
+		return false
+	}
+	rs := s.rangeSet(getMeshIDLevelIndex(c.level))
+	index := c.index()
+	i := sort.Search(len(rs.ranges), func(i int) bool { return rs.ranges[i].end > index })
+	return i < len(rs.ranges) && rs.ranges[i].start <= index
+}
+
+// Len 含まれる地域メッシュコードの数を取得する。
+func (s *MeshSet) Len() int {
+	var n uint64
+	for i := range s.levels {
+		for _, r := range s.rangeSet(i).ranges {
+			n += r.end - r.start
+		}
+	}
+	return int(n)
+}
+
+// Union 和集合を取得する。
+func (s *MeshSet) Union(other *MeshSet) *MeshSet {
+	return s.combine(other, func(a, b bool) bool { return a || b })
+}
+
+// Intersect 積集合を取得する。
+func (s *MeshSet) Intersect(other *MeshSet) *MeshSet {
+	return s.combine(other, func(a, b bool) bool { return a && b })
+}
+
+// Difference 差集合(s に含まれ other に含まれないメッシュ)を取得する。
+func (s *MeshSet) Difference(other *MeshSet) *MeshSet {
+	return s.combine(other, func(a, b bool) bool { return a && !b })
+}
+
+// Iterate レベル順、各レベルでは南から北、西から東の順に地域メッシュコードを fn に渡す。
+// fn が false を返すと終了する。
+func (s *MeshSet) Iterate(fn func(code MeshCode) bool) {
+	for i, level := range meshIDLevels {
+		n := uint64(getCellCount(level))
+		for _, r := range s.rangeSet(i).ranges {
+			for index := r.start; index < r.end; index++ {
+				c := cell{level: level, y: int(index / (100 * n)), x: int(index % (100 * n))}
+				code, err := c.toCode()
+				if err != nil {
+					continue
+				}
+				if !fn(code) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Codes 含まれる地域メッシュコードを Iterate の順で取得する。
+func (s *MeshSet) Codes() MeshCodes {
+	codes := make(MeshCodes, 0, s.Len())
+	s.Iterate(func(code MeshCode) bool {
+		codes = append(codes, code)
+		return true
+	})
+	return codes
+}
+
+// MarshalBinary encoding.BinaryMarshaler の実装
+func (s *MeshSet) MarshalBinary() ([]byte, error) {
+	b := []byte{meshSetVersion}
+	for i := range s.levels {
+		rs := s.rangeSet(i)
+		if len(rs.ranges) == 0 {
+			continue
+		}
+		b = append(b, byte(i))
+		b = appendUvarint(b, uint64(len(rs.ranges)))
+		var prev uint64
+		for _, r := range rs.ranges {
+			// 直前の範囲の終端からの差分
+			b = appendUvarint(b, r.start-prev)
+			b = appendUvarint(b, r.end-r.start)
+			prev = r.end
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary encoding.BinaryUnmarshaler の実装
+func (s *MeshSet) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != meshSetVersion {
+		return ErrInvalidMeshSet
+	}
+	var levels [len(meshIDLevels)]rangeSet
+	data = data[1:]
+	readUvarint := func() (uint64, error) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, ErrInvalidMeshSet
+		}
+		data = data[n:]
+		return v, nil
+	}
+	for len(data) > 0 {
+		i := int(data[0])
+		data = data[1:]
+		if i <= 0 || i >= len(levels) || levels[i].ranges != nil {
+			return ErrInvalidMeshSet
+		}
+		count, err := readUvarint()
+		if err != nil {
+			return err
+		}
+		if count == 0 || count > uint64(len(data)) {
+			return ErrInvalidMeshSet
+		}
+		// 通し番号は 100*n 行 × 100*n 列の格子の範囲内に収まる
+		n := uint64(getCellCount(meshIDLevels[i]))
+		size := 100 * n * 100 * n
+		ranges := make([]meshRange, 0, count)
+		var prev uint64
+		for j := uint64(0); j < count; j++ {
+			gap, err := readUvarint()
+			if err != nil {
+				return err
+			}
+			length, err := readUvarint()
+			if err != nil {
+				return err
+			}
+			if (j > 0 && gap == 0) || length == 0 {
+				return ErrInvalidMeshSet
+			}
+			// prev <= size のため、差で比較すれば uint64 の桁あふれは起きない
+			if gap > size-prev || length > size-prev-gap {
+				return ErrInvalidMeshSet
+			}
+			r := meshRange{start: prev + gap, end: prev + gap + length}
+			ranges = append(ranges, r)
+			prev = r.end
+		}
+		levels[i].ranges = ranges
+	}
+	s.levels = levels
+	return nil
+}
+
+// rangeSet 追加された通し番号を反映した i 番目のレベルの範囲を取得する。
+func (s *MeshSet) rangeSet(i int) *rangeSet {
+	rs := &s.levels[i]
+	if len(rs.pending) > 0 {
+		rs.flush()
+	}
+	return rs
+}
+
+func (s *MeshSet) combine(other *MeshSet, op func(a, b bool) bool) *MeshSet {
+	result := &MeshSet{}
+	for i := range s.levels {
+		result.levels[i].ranges = combineRanges(s.rangeSet(i).ranges, other.rangeSet(i).ranges, op)
+	}
+	return result
+}
+
+// flush 追加された通し番号を並べ替えて範囲にまとめる。
+func (rs *rangeSet) flush() {
+	sort.Slice(rs.pending, func(i, j int) bool { return rs.pending[i] < rs.pending[j] })
+	added := make([]meshRange, 0, len(rs.pending))
+	for _, index := range rs.pending {
+		if n := len(added); n > 0 && added[n-1].end >= index {
+			if added[n-1].end == index {
+				added[n-1].end++
+			}
+			continue
+		}
+		added = append(added, meshRange{start: index, end: index + 1})
+	}
+	rs.pending = nil
+	rs.ranges = combineRanges(rs.ranges, added, func(a, b bool) bool { return a || b })
+}
+
+// combineRanges 2つの範囲の一覧を、各位置が含まれるかを op で判定して結合する。
+func combineRanges(a, b []meshRange, op func(a, b bool) bool) []meshRange {
+	// 範囲の境界を順に走査する
+	var result []meshRange
+	var i, j int
+	var inA, inB bool
+	var start uint64
+	inResult := false
+	for i < len(a)*2 || j < len(b)*2 {
+		var pos uint64
+		nextA, nextB := ^uint64(0), ^uint64(0)
+		if i < len(a)*2 {
+			nextA = boundary(a, i)
+		}
+		if j < len(b)*2 {
+			nextB = boundary(b, j)
+		}
+		pos = nextA
+		if nextB < pos {
+			pos = nextB
+		}
+		if nextA == pos {
+			inA = i%2 == 0
+			i++
+		}
+		if nextB == pos {
+			inB = j%2 == 0
+			j++
+		}
+		in := op(inA, inB)
+		if in && !inResult {
+			start = pos
+		} else if !in && inResult {
+			result = append(result, meshRange{start: start, end: pos})
+		}
+		inResult = in
+	}
+	return result
+}
+
+// boundary 範囲の一覧の i 番目の境界(偶数は開始、奇数は終了)
+func boundary(ranges []meshRange, i int) uint64 {
+	if i%2 == 0 {
+		return ranges[i/2].start
+	}
+	return ranges[i/2].end
+}
+
+// index 格子上の位置を、レベルごとの通し番号に変換する。
+func (c cell) index() uint64 {
+	n := uint64(getCellCount(c.level))
+	return uint64(c.y)*100*n + uint64(c.x)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
@@ -0,0 +1,117 @@
+package japanmesh
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMeshSet(t *testing.T) {
+	service, err := NewMeshSet(
+		"53394500", "53394501", "53394502", "53394510", "53394511", "53394512",
+		"533945", "53394547112",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flood, err := NewMeshSet("53394501", "53394511", "53394521", "533945")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if service.Len() != 8 || !service.Contains("53394511") || service.Contains("53394521") || service.Contains("5339") {
+		t.Errorf("MeshSet got = %v", service.Codes())
+	}
+	if _, err := NewMeshSet("1"); err != ErrInvalidMeshCode {
+		t.Errorf("NewMeshSet() error = %v, wantErr %v", err, ErrInvalidMeshCode)
+	}
+
+	tests := []struct {
+		name string
+		got  *MeshSet
+		want MeshCodes
+	}{
+		{
+			name: "union",
+			got:  service.Union(flood),
+			want: MeshCodes{"533945", "53394500", "53394501", "53394502", "53394510", "53394511", "53394512", "53394521", "53394547112"},
+		},
+		{
+			name: "intersect",
+			got:  service.Intersect(flood),
+			want: MeshCodes{"533945", "53394501", "53394511"},
+		},
+		{
+			name: "difference",
+			got:  service.Difference(flood),
+			want: MeshCodes{"53394500", "53394502", "53394510", "53394512", "53394547112"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.Codes(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MeshSet got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeshSet_AcrossLevel1(t *testing.T) {
+	// 第1次地域区画の境界をまたいで東西に連続するメッシュは1つの範囲になる
+	s, _ := NewMeshSet("53394779", "53404070", "53394789", "53394779")
+	if got := s.Codes(); !reflect.DeepEqual(got, MeshCodes{"53394779", "53404070", "53394789"}) {
+		t.Errorf("MeshSet got = %v", got)
+	}
+	if n := len(s.levels[getMeshIDLevelIndex(Level3)].ranges); n != 2 {
+		t.Errorf("MeshSet ranges = %v", s.levels[getMeshIDLevelIndex(Level3)].ranges)
+	}
+	_ = s.Add("53404080")
+	if !s.Contains("53404080") || s.Len() != 4 || len(s.levels[getMeshIDLevelIndex(Level3)].ranges) != 2 {
+		t.Errorf("MeshSet got = %v", s.Codes())
+	}
+}
+
+func TestMeshSet_Iterate(t *testing.T) {
+	s, _ := NewMeshSet("533945", "533946", "533947")
+	var got MeshCodes
+	s.Iterate(func(code MeshCode) bool {
+		got = append(got, code)
+		return len(got) < 2
+	})
+	if !reflect.DeepEqual(got, MeshCodes{"533945", "533946"}) {
+		t.Errorf("MeshSet.Iterate() got = %v", got)
+	}
+}
+
+func TestMeshSet_MarshalBinary(t *testing.T) {
+	codes, _ := GetCodes("533945")
+	s, _ := NewMeshSet(codes...)
+	_ = s.Add("5339")
+	_ = s.Add("53394547112")
+	b, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 100個の基準地域メッシュは10個の範囲にまとまる
+	if len(b) > 64 {
+		t.Errorf("MeshSet.MarshalBinary() len = %d", len(b))
+	}
+
+	var got MeshSet
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Codes(), s.Codes()) {
+		t.Errorf("MeshSet.UnmarshalBinary() got = %v, want %v", got.Codes(), s.Codes())
+	}
+
+	invalid := [][]byte{nil, {2}, {1, 3}, {1, 3, 1}, {1, 3, 2, 0, 1, 0, 1}}
+	// 第1次地域区画の格子(100×100)を超える範囲
+	invalid = append(invalid, appendUvarint(appendUvarint([]byte{1, 1, 1}, 9999), 2))
+	// prev+gap+length が uint64 を超える範囲
+	invalid = append(invalid, appendUvarint(appendUvarint([]byte{1, 1, 1}, 1<<63), 1<<63))
+	for _, data := range invalid {
+		if err := got.UnmarshalBinary(data); err != ErrInvalidMeshSet {
+			t.Errorf("MeshSet.UnmarshalBinary(%v) error = %v, wantErr %v", data, err, ErrInvalidMeshSet)
+		}
+	}
+}
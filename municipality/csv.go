@@ -0,0 +1,105 @@
+package municipality
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// ReadCSV 「市区町村別メッシュ・コード一覧」の CSV から対応表を作成する。version は対応表の版とする。
+//
+// CSV は Shift_JIS または UTF-8 で、1行目の列名から「メッシュ」を含む列を第3次地域区画のコード、
+// それ以外で「コード」を含む列を市区町村コード、「名」を含む列を市区町村名とする。
+// 都道府県ごとのファイルを複数指定できる。
+func ReadCSV(version string, files ...io.Reader) (*Table, error) {
+	t := newTable(version)
+	for i, f := range files {
+		if err := t.readCSV(f); err != nil {
+			return nil, fmt.Errorf("file %d: %w", i+1, err)
+		}
+	}
+	t.build()
+	return t, nil
+}
+
+func (t *Table) readCSV(f io.Reader) error {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	if !utf8.Valid(data) {
+		if data, err = japanese.ShiftJIS.NewDecoder().Bytes(data); err != nil {
+			return err
+		}
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("%w: missing header", ErrInvalidMunicipalityTable)
+	}
+	meshIndex, codeIndex, nameIndex := -1, -1, -1
+	for i, name := range header {
+		switch {
+		case strings.Contains(name, "メッシュ"):
+			meshIndex = i
+		case strings.Contains(name, "コード"):
+			codeIndex = i
+		case strings.Contains(name, "名"):
+			nameIndex = i
+		}
+	}
+	if meshIndex < 0 || codeIndex < 0 || nameIndex < 0 {
+		return fmt.Errorf("%w: unknown header %q", ErrInvalidMunicipalityTable, header)
+	}
+	for {
+		fields, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := r.FieldPos(0)
+		if meshIndex >= len(fields) || codeIndex >= len(fields) || nameIndex >= len(fields) {
+			return fmt.Errorf("%w: line %d: missing column", ErrInvalidMunicipalityTable, line)
+		}
+		code := normalizeMunicipalityCode(fields[codeIndex])
+		if code == "" {
+			return fmt.Errorf("%w: line %d: invalid municipality code %q", ErrInvalidMunicipalityTable, line, fields[codeIndex])
+		}
+		t.municipalities[code] = Municipality{Code: code, Name: strings.TrimSpace(fields[nameIndex])}
+		if err := t.add(japanmesh.MeshCode(strings.TrimSpace(fields[meshIndex])), code); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidMunicipalityTable, line, err)
+		}
+	}
+}
+
+// normalizeMunicipalityCode 市区町村コードを5桁にする。検査数字を含む6桁のコードは検査数字を除く。
+func normalizeMunicipalityCode(s string) string {
+	s = strings.TrimSpace(s)
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	switch len(s) {
+	case 4:
+		// 先頭の0が落ちたコード
+		return "0" + s
+	case 5:
+		return s
+	case 6:
+		return s[:5]
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+// Command gen 「市区町村別メッシュ・コード一覧」の CSV から、municipality パッケージに埋め込む対応表を作成する。
+//
+//	go run ./municipality/internal/gen -version 2020 -o municipality/municipality.tsv.gz path/to/*.csv
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/keitaro1020/go-japanmesh/municipality"
+)
+
+func main() {
+	version := flag.String("version", "", "version of the mesh code list (e.g. 2020)")
+	out := flag.String("o", "municipality.tsv.gz", "output file")
+	flag.Parse()
+	if *version == "" || flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: gen -version VERSION [-o FILE] CSV...")
+		os.Exit(2)
+	}
+	if err := run(*version, *out, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(version, out string, names []string) error {
+	files := make([]io.Reader, 0, len(names))
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		files = append(files, f)
+	}
+	table, err := municipality.ReadCSV(version, files...)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := table.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,307 @@
+// Package municipality は第3次地域区画(基準地域メッシュ)と都道府県・市区町村の対応を検索する。
+//
+// 対応表は総務省統計局の「市区町村別メッシュ・コード一覧」から作成し、municipality.tsv.gz として埋め込む。
+// 一覧は市区町村ごとに、区域にかかる第3次地域区画を列挙したもので、
+// 複数の市区町村の境界にまたがるメッシュはそれぞれの市区町村に含まれる。
+//
+// 対応表は次のコマンドで、一覧の CSV(Shift_JIS または UTF-8)から作成する。
+//
+//	go run ./municipality/internal/gen -version 2020 -o municipality/municipality.tsv.gz path/to/*.csv
+//
+// 現在は一覧のデータを同梱しておらず、municipality.tsv.gz は見出しと空の版だけを持つ。
+// 対応表を作成して置き換えるまで、パッケージの関数は ErrTableNotAvailable を返す。
+// ReadCSV で一覧の CSV から直接 Table を作成することもできる。
+package municipality
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+)
+
+var (
+	ErrTableNotAvailable        = errors.New("municipality table is not available")
+	ErrMunicipalityNotFound     = errors.New("municipality not found")
+	ErrInvalidMunicipalityTable = errors.New("invalid municipality table")
+)
+
+//go:embed municipality.tsv.gz
+var embeddedTable []byte
+
+// tableHeader 対応表の形式を表す1行目
+const tableHeader = "japanmesh-municipality\t1"
+
+// prefectures 都道府県コード順の都道府県名
+var prefectures = [...]string{
+	"北海道", "青森県", "岩手県", "宮城県", "秋田県", "山形県", "福島県",
+	"茨城県", "栃木県", "群馬県", "埼玉県", "千葉県", "東京都", "神奈川県",
+	"新潟県", "富山県", "石川県", "福井県", "山梨県", "長野県", "岐阜県",
+	"静岡県", "愛知県", "三重県", "滋賀県", "京都府", "大阪府", "兵庫県",
+	"奈良県", "和歌山県", "鳥取県", "島根県", "岡山県", "広島県", "山口県",
+	"徳島県", "香川県", "愛媛県", "高知県", "福岡県", "佐賀県", "長崎県",
+	"熊本県", "大分県", "宮崎県", "鹿児島県", "沖縄県",
+}
+
+// Municipality 市区町村
+type Municipality struct {
+	// 全国地方公共団体コード(JIS X 0402 の5桁、検査数字を除く)
+	Code string
+	Name string
+}
+
+// PrefectureCode 都道府県コード(2桁)
+func (m Municipality) PrefectureCode() string {
+	if len(m.Code) < 2 {
+		return ""
+	}
+	return m.Code[:2]
+}
+
+// PrefectureName 都道府県名
+func (m Municipality) PrefectureName() string {
+	var n int
+	if _, err := fmt.Sscanf(m.PrefectureCode(), "%d", &n); err != nil || n < 1 || n > len(prefectures) {
+		return ""
+	}
+	return prefectures[n-1]
+}
+
+// meshEntry 第3次地域区画と、区域がかかる市区町村の組
+type meshEntry struct {
+	code         japanmesh.MeshCode
+	municipality string
+}
+
+// Table 第3次地域区画と市区町村の対応表
+type Table struct {
+	version        string
+	municipalities map[string]Municipality
+	// メッシュコード、市区町村コードの昇順
+	entries []meshEntry
+	// 市区町村ごとのメッシュコード(昇順)
+	meshes map[string]japanmesh.MeshCodes
+}
+
+var (
+	defaultTable     *Table
+	defaultTableErr  error
+	defaultTableOnce sync.Once
+)
+
+// Default 埋め込まれた対応表を返す。対応表が埋め込まれていない場合は ErrTableNotAvailable を返す。
+func Default() (*Table, error) {
+	defaultTableOnce.Do(func() {
+		defaultTable, defaultTableErr = readTable(bytes.NewReader(embeddedTable))
+		if defaultTableErr == nil && len(defaultTable.entries) == 0 {
+			defaultTable, defaultTableErr = nil, ErrTableNotAvailable
+		}
+	})
+	return defaultTable, defaultTableErr
+}
+
+// Version 埋め込まれた対応表の版。対応表が埋め込まれていない場合は空文字を返す。
+func Version() string {
+	t, err := Default()
+	if err != nil {
+		return ""
+	}
+	return t.Version()
+}
+
+// MunicipalitiesOf 埋め込まれた対応表から、地域メッシュにかかる市区町村を市区町村コードの昇順で取得する。
+// 詳細は Table.MunicipalitiesOf を参照。
+func MunicipalitiesOf(code japanmesh.MeshCode) ([]Municipality, error) {
+	t, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return t.MunicipalitiesOf(code)
+}
+
+// MeshesOf 埋め込まれた対応表から、市区町村の区域にかかる指定したレベルの地域メッシュコードを取得する。
+// 詳細は Table.MeshesOf を参照。
+func MeshesOf(municipalityCode string, level japanmesh.Level) (japanmesh.MeshCodes, error) {
+	t, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return t.MeshesOf(municipalityCode, level)
+}
+
+// Version 対応表の版
+func (t *Table) Version() string {
+	return t.version
+}
+
+// Municipality 市区町村コードから市区町村を取得する。
+func (t *Table) Municipality(code string) (Municipality, bool) {
+	m, ok := t.municipalities[code]
+	return m, ok
+}
+
+// MunicipalitiesOf 地域メッシュにかかる市区町村を市区町村コードの昇順で取得する。
+//
+// 第3次地域区画より下位のメッシュは、そのメッシュを含む第3次地域区画にかかる市区町村とし、
+// 第1次・第2次地域区画は、区画内の第3次地域区画のいずれかにかかる市区町村とする。
+// 統合地域メッシュは ErrInvalidLevel を返す。
+func (t *Table) MunicipalitiesOf(code japanmesh.MeshCode) ([]Municipality, error) {
+	if err := japanmesh.Validate(code); err != nil {
+		return nil, err
+	}
+	level, err := japanmesh.GetLevel(code)
+	if err != nil {
+		return nil, err
+	}
+	switch level {
+	case japanmesh.LevelTwofold, japanmesh.LevelFivefold:
+		return nil, japanmesh.ErrInvalidLevel
+	case japanmesh.LevelHalf, japanmesh.LevelQuarter, japanmesh.LevelOneEighth:
+		code = code[:8]
+	}
+
+	seen := map[string]struct{}{}
+	i := sort.Search(len(t.entries), func(i int) bool { return t.entries[i].code >= code })
+	for ; i < len(t.entries) && strings.HasPrefix(string(t.entries[i].code), string(code)); i++ {
+		seen[t.entries[i].municipality] = struct{}{}
+	}
+	result := make([]Municipality, 0, len(seen))
+	for c := range seen {
+		result = append(result, t.municipalities[c])
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return result, nil
+}
+
+// MeshesOf 市区町村の区域にかかる指定したレベルの地域メッシュコードを昇順で取得する。
+//
+// 第1次・第2次地域区画は区域にかかる第3次地域区画を含む区画とする。
+// 第3次地域区画より下位のレベルは区域にかかる第3次地域区画を分割したメッシュとし、区域にかからないメッシュも含む。
+// 市区町村が対応表にない場合は ErrMunicipalityNotFound、統合地域メッシュは ErrInvalidLevel を返す。
+func (t *Table) MeshesOf(municipalityCode string, level japanmesh.Level) (japanmesh.MeshCodes, error) {
+	codes, ok := t.meshes[municipalityCode]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMunicipalityNotFound, municipalityCode)
+	}
+	switch level {
+	case japanmesh.Level3:
+		return append(japanmesh.MeshCodes(nil), codes...), nil
+	case japanmesh.Level1, japanmesh.Level2:
+		var result japanmesh.MeshCodes
+		for _, code := range codes {
+			for _, part := range japanmesh.SplitCodeByLevel(code) {
+				// codes は昇順のため、上位の区画は連続する
+				if l, _ := japanmesh.GetLevel(part); l == level && (len(result) == 0 || result[len(result)-1] != part) {
+					result = append(result, part)
+				}
+			}
+		}
+		return result, nil
+	case japanmesh.LevelHalf, japanmesh.LevelQuarter, japanmesh.LevelOneEighth:
+		return japanmesh.Uncompact(codes, level)
+	}
+	return nil, japanmesh.ErrInvalidLevel
+}
+
+// Write 対応表を埋め込み用の形式(gzip で圧縮したタブ区切りのテキスト)で書き出す。
+func (t *Table) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	bw := bufio.NewWriter(zw)
+	fmt.Fprintf(bw, "%s\nversion\t%s\n", tableHeader, t.version)
+	codes := make([]string, 0, len(t.municipalities))
+	for code := range t.municipalities {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(bw, "m\t%s\t%s\n", code, t.municipalities[code].Name)
+	}
+	for _, e := range t.entries {
+		fmt.Fprintf(bw, "%s\t%s\n", e.code, e.municipality)
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// readTable Write で書き出した対応表を読み込む。
+func readTable(r io.Reader) (*Table, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMunicipalityTable, err)
+	}
+	defer zr.Close()
+	t := newTable("")
+	s := bufio.NewScanner(zr)
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Split(s.Text(), "\t")
+		switch {
+		case line == 1:
+			if s.Text() != tableHeader {
+				return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidMunicipalityTable, s.Text())
+			}
+		case fields[0] == "version" && len(fields) == 2:
+			t.version = fields[1]
+		case fields[0] == "m" && len(fields) == 3:
+			t.municipalities[fields[1]] = Municipality{Code: fields[1], Name: fields[2]}
+		case len(fields) == 2:
+			if err := t.add(japanmesh.MeshCode(fields[0]), fields[1]); err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidMunicipalityTable, line, err)
+			}
+		default:
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidMunicipalityTable, line)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMunicipalityTable, err)
+	}
+	t.build()
+	return t, nil
+}
+
+func newTable(version string) *Table {
+	return &Table{version: version, municipalities: map[string]Municipality{}, meshes: map[string]japanmesh.MeshCodes{}}
+}
+
+// add 第3次地域区画と市区町村の組を追加する。
+func (t *Table) add(code japanmesh.MeshCode, municipality string) error {
+	if err := japanmesh.Validate(code); err != nil {
+		return fmt.Errorf("%w: %q", err, code)
+	}
+	if level, _ := japanmesh.GetLevel(code); level != japanmesh.Level3 {
+		return fmt.Errorf("%w: %q", japanmesh.ErrInvalidLevel, code)
+	}
+	if _, ok := t.municipalities[municipality]; !ok {
+		return fmt.Errorf("%w: %q", ErrMunicipalityNotFound, municipality)
+	}
+	t.entries = append(t.entries, meshEntry{code: code, municipality: municipality})
+	return nil
+}
+
+// build 組を並べ替えて重複を除き、市区町村ごとのメッシュコードを求める。
+func (t *Table) build() {
+	sort.Slice(t.entries, func(i, j int) bool {
+		if t.entries[i].code != t.entries[j].code {
+			return t.entries[i].code < t.entries[j].code
+		}
+		return t.entries[i].municipality < t.entries[j].municipality
+	})
+	entries := t.entries[:0]
+	for _, e := range t.entries {
+		if len(entries) > 0 && e == entries[len(entries)-1] {
+			continue
+		}
+		entries = append(entries, e)
+		t.meshes[e.municipality] = append(t.meshes[e.municipality], e.code)
+	}
+	t.entries = entries
+}
@@ -0,0 +1,210 @@
+package municipality
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// テスト用の一覧。53394525 は2つの市区町村にまたがる
+const (
+	testCSV1 = "都道府県市区町村コード,市区町村名,基準メッシュ・コード\n" +
+		"13104,新宿区,53394525\n" +
+		"13104,新宿区,53394526\n" +
+		"13104,新宿区,53394536\n"
+	testCSV2 = "\"都道府県市区町村コード\",\"市区町村名\",\"基準メッシュ・コード\"\r\n" +
+		"\"131130\",\"渋谷区\",\"53394525\"\r\n" +
+		"\"131130\",\"渋谷区\",\"53394515\"\r\n" +
+		"\"131130\",\"渋谷区\",\"53394515\"\r\n" +
+		"\"1101\",\"札幌市中央区\",\"64414277\"\r\n"
+)
+
+func newTestTable(t *testing.T) *Table {
+	t.Helper()
+	sjis, err := japanese.ShiftJIS.NewEncoder().String(testCSV2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := ReadCSV("test", strings.NewReader(testCSV1), strings.NewReader(sjis))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func codesOf(ms []Municipality) []string {
+	codes := make([]string, len(ms))
+	for i, m := range ms {
+		codes[i] = m.Code
+	}
+	return codes
+}
+
+func TestTable_MunicipalitiesOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    japanmesh.MeshCode
+		want    []string
+		wantErr error
+	}{
+		{name: "level3", code: "53394526", want: []string{"13104"}},
+		{name: "straddle", code: "53394525", want: []string{"13104", "13113"}},
+		{name: "quarter", code: "5339452524", want: []string{"13104", "13113"}},
+		{name: "level2", code: "533945", want: []string{"13104", "13113"}},
+		{name: "level1", code: "6441", want: []string{"01101"}},
+		{name: "not found", code: "53394547", want: []string{}},
+		{name: "twofold", code: "533945245", wantErr: japanmesh.ErrInvalidLevel},
+		{name: "invalid", code: "53394", wantErr: japanmesh.ErrInvalidMeshCode},
+	}
+	table := newTestTable(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := table.MunicipalitiesOf(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MunicipalitiesOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(codesOf(got), tt.want) {
+				t.Errorf("MunicipalitiesOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	got, _ := table.MunicipalitiesOf("53394525")
+	if got[1].Name != "渋谷区" || got[1].PrefectureCode() != "13" || got[1].PrefectureName() != "東京都" {
+		t.Errorf("MunicipalitiesOf() = %+v", got[1])
+	}
+}
+
+func TestTable_MeshesOf(t *testing.T) {
+	tests := []struct {
+		name         string
+		municipality string
+		level        japanmesh.Level
+		want         japanmesh.MeshCodes
+		wantErr      error
+	}{
+		{name: "level3", municipality: "13104", level: japanmesh.Level3, want: japanmesh.MeshCodes{"53394525", "53394526", "53394536"}},
+		{name: "level2", municipality: "13113", level: japanmesh.Level2, want: japanmesh.MeshCodes{"533945"}},
+		{name: "level1", municipality: "01101", level: japanmesh.Level1, want: japanmesh.MeshCodes{"6441"}},
+		{name: "half", municipality: "13113", level: japanmesh.LevelHalf,
+			want: japanmesh.MeshCodes{"533945151", "533945152", "533945153", "533945154", "533945251", "533945252", "533945253", "533945254"}},
+		{name: "unknown", municipality: "99999", level: japanmesh.Level3, wantErr: ErrMunicipalityNotFound},
+		{name: "fivefold", municipality: "13104", level: japanmesh.LevelFivefold, wantErr: japanmesh.ErrInvalidLevel},
+	}
+	table := newTestTable(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := table.MeshesOf(tt.municipality, tt.level)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MeshesOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MeshesOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTable_Write(t *testing.T) {
+	table := newTestTable(t)
+	var buf bytes.Buffer
+	if err := table.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readTable(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, table) {
+		t.Errorf("readTable() = %+v, want %+v", got, table)
+	}
+	if _, err := readTable(strings.NewReader("japanmesh-municipality\t1\n")); !errors.Is(err, ErrInvalidMunicipalityTable) {
+		t.Errorf("readTable() error = %v, want %v", err, ErrInvalidMunicipalityTable)
+	}
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "empty", content: ""},
+		{name: "unknown header", content: "code,name,mesh\n13104,新宿区,53394525\n"},
+		{name: "invalid mesh", content: "市区町村コード,市区町村名,メッシュコード\n13104,新宿区,5339452\n"},
+		{name: "not level3", content: "市区町村コード,市区町村名,メッシュコード\n13104,新宿区,533945\n"},
+		{name: "invalid municipality", content: "市区町村コード,市区町村名,メッシュコード\n131,新宿区,53394525\n"},
+		{name: "missing column", content: "市区町村コード,市区町村名,メッシュコード\n13104,新宿区\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadCSV("test", strings.NewReader(tt.content)); !errors.Is(err, ErrInvalidMunicipalityTable) {
+				t.Errorf("ReadCSV() error = %v, want %v", err, ErrInvalidMunicipalityTable)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if Version() == "" {
+		// 対応表を埋め込まずに配布している場合
+		if _, err := MunicipalitiesOf("53394525"); !errors.Is(err, ErrTableNotAvailable) {
+			t.Errorf("MunicipalitiesOf() error = %v, want %v", err, ErrTableNotAvailable)
+		}
+		if _, err := MeshesOf("13104", japanmesh.Level3); !errors.Is(err, ErrTableNotAvailable) {
+			t.Errorf("MeshesOf() error = %v, want %v", err, ErrTableNotAvailable)
+		}
+		// 一覧のデータを同梱するまで、以降の実データでの確認は行わない
+		t.Skip("municipality.tsv.gz is an empty placeholder; real-data checks are blocked until the table is generated with go run ./municipality/internal/gen")
+	}
+	tests := []struct {
+		name string
+		code japanmesh.MeshCode
+		want []string
+	}{
+		// 東京都庁の位置する基準地域メッシュは新宿区
+		{name: "shinjuku", code: "53394525", want: []string{"13104"}},
+		// 東京駅の位置する基準地域メッシュは千代田区と中央区の境界にかかる
+		{name: "boundary", code: "53394611", want: []string{"13101", "13102"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MunicipalitiesOf(tt.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			codes := strings.Join(codesOf(got), ",")
+			for _, want := range tt.want {
+				if !strings.Contains(codes, want) {
+					t.Errorf("MunicipalitiesOf(%v) = %v, want to include %v", tt.code, got, want)
+				}
+			}
+			if len(tt.want) > 1 && len(got) < 2 {
+				t.Errorf("MunicipalitiesOf(%v) = %v, want more than one municipality", tt.code, got)
+			}
+		})
+	}
+	meshes, err := MeshesOf("13104", japanmesh.Level3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, code := range meshes {
+		found = found || code == "53394525"
+	}
+	if !found {
+		t.Errorf("MeshesOf(13104) = %v, want to include 53394525", meshes)
+	}
+}
+
+func TestMunicipality_PrefectureName(t *testing.T) {
+	for code, want := range map[string]string{"01101": "北海道", "13104": "東京都", "47201": "沖縄県", "48001": "", "": ""} {
+		if got := (Municipality{Code: code}).PrefectureName(); got != want {
+			t.Errorf("PrefectureName(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
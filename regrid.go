@@ -0,0 +1,141 @@
+package japanmesh
+
+import (
+	"math"
+	"sort"
+)
+
+// RegridMode Regrid で値を変換する方法
+type RegridMode int
+
+const (
+	// RegridSum 人口や件数など、面積に比例する値として変換する。
+	// 変換元のメッシュの値を、重なる面積の割合で変換先のメッシュに配分して合計する。
+	RegridSum RegridMode = iota
+	// RegridMean 密度や割合など、面積に依存しない値として変換する。
+	// 変換先のメッシュの値を、重なる変換元のメッシュの値の面積加重平均とする。
+	RegridMean
+)
+
+// RegridResult Regrid の変換結果
+type RegridResult struct {
+	Values map[MeshCode]float64
+	// 変換先のメッシュのうち、変換元のメッシュに覆われている面積の割合(0〜1)
+	Coverage map[MeshCode]float64
+	// 変換元のメッシュに一部しか覆われていない変換先のメッシュ(昇順)
+	Partial MeshCodes
+}
+
+// regridCoverageTolerance 全体が覆われているとみなす面積の割合の誤差
+const regridCoverageTolerance = 1e-9
+
+// Regrid 地域メッシュごとの値を、指定したレベルのメッシュの値に変換する。
+// 変換元と変換先のメッシュは入れ子である必要はなく、統合地域メッシュ(2倍・5倍地域メッシュ)を含む
+// 任意のレベルの間で、重なる部分の面積で重み付けして変換する。
+// values には異なるレベルのメッシュが混在していてもよい。
+func Regrid(values map[MeshCode]float64, level Level, mode RegridMode) (*RegridResult, error) {
+	if _, ok := getMesh(level); !ok {
+		return nil, ErrInvalidLevel
+	}
+	nTarget := getCellCount(level)
+
+	// 浮動小数点の演算順序を一定にするため並べ替える
+	codes := make(MeshCodes, 0, len(values))
+	for code := range values {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	sums := make(map[cell]float64)
+	areas := make(map[cell]float64)
+	for _, code := range codes {
+		source, err := toCell(code)
+		if err != nil {
+			return nil, err
+		}
+		nSource := getCellCount(source.level)
+		// 変換元と変換先の両方の境界を表せる細かさの格子
+		n := lcm(nSource, nTarget)
+		scaleSource, scaleTarget := n/nSource, n/nTarget
+		y0, y1 := source.y*scaleSource, (source.y+1)*scaleSource
+		x0, x1 := source.x*scaleSource, (source.x+1)*scaleSource
+		sourceArea := source.bounds().Area()
+
+		for ty := y0 / scaleTarget; ty*scaleTarget < y1; ty++ {
+			for tx := x0 / scaleTarget; tx*scaleTarget < x1; tx++ {
+				target := cell{level: level, y: ty, x: tx}
+				overlap := gridBounds(
+					n,
+					maxInt(y0, ty*scaleTarget), minInt(y1, (ty+1)*scaleTarget),
+					maxInt(x0, tx*scaleTarget), minInt(x1, (tx+1)*scaleTarget),
+				).Area()
+				switch mode {
+				case RegridMean:
+					sums[target] += values[code] * overlap
+				default:
+					sums[target] += values[code] * overlap / sourceArea
+				}
+				areas[target] += overlap
+			}
+		}
+	}
+
+	result := &RegridResult{
+		Values:   make(map[MeshCode]float64, len(sums)),
+		Coverage: make(map[MeshCode]float64, len(sums)),
+	}
+	for target, sum := range sums {
+		code, err := target.toCode()
+		if err != nil {
+			// 日本の国土にかからない第1次地域区画
+			continue
+		}
+		value := sum
+		if mode == RegridMean {
+			value = sum / areas[target]
+		}
+		coverage := math.Min(areas[target]/target.bounds().Area(), 1)
+		result.Values[code] = value
+		result.Coverage[code] = coverage
+		if coverage < 1-regridCoverageTolerance {
+			result.Partial = append(result.Partial, code)
+		}
+	}
+	sort.Slice(result.Partial, func(i, j int) bool { return result.Partial[i] < result.Partial[j] })
+	return result, nil
+}
+
+// gridBounds 第1次地域区画の一辺を n 分割した格子上の範囲 [y0, y1) × [x0, x1) を緯度経度の範囲に変換する。
+func gridBounds(n, y0, y1, x0, x1 int) Bounds {
+	lat := level1Mesh.Distance.Lat / float64(n)
+	lng := level1Mesh.Distance.Lng / float64(n)
+	return Bounds{
+		Min: GeoCode{Latitude: float64(y0) * lat, Longitude: 100 + float64(x0)*lng},
+		Max: GeoCode{Latitude: float64(y1) * lat, Longitude: 100 + float64(x1)*lng},
+	}
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func lcm(a, b int) int {
+	return a / gcd(a, b) * b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,89 @@
+package japanmesh
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRegrid_Nested(t *testing.T) {
+	values := make(map[MeshCode]float64)
+	children, _ := GetCodes("533945")
+	for _, code := range children {
+		values[code] = 1
+	}
+	values["53394600"] = 1
+
+	got, err := Regrid(values, Level2, RegridSum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got.Values["533945"]-100) > 1e-9 || math.Abs(got.Values["533946"]-1) > 1e-9 {
+		t.Errorf("Regrid() values = %v", got.Values)
+	}
+	if got.Coverage["533945"] != 1 || math.Abs(got.Coverage["533946"]-0.01) > 1e-4 {
+		t.Errorf("Regrid() coverage = %v", got.Coverage)
+	}
+	if want := (MeshCodes{"533946"}); !reflect.DeepEqual(got.Partial, want) {
+		t.Errorf("Regrid() partial = %v, want %v", got.Partial, want)
+	}
+
+	got, err = Regrid(map[MeshCode]float64{"533945": 100}, Level3, RegridSum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum float64
+	for _, v := range got.Values {
+		sum += v
+	}
+	if len(got.Values) != 100 || math.Abs(sum-100) > 1e-9 || len(got.Partial) != 0 {
+		t.Errorf("Regrid() values = %v, partial = %v", got.Values, got.Partial)
+	}
+	// 南側のメッシュほど面積が大きい
+	if got.Values["53394500"] <= got.Values["53394590"] {
+		t.Errorf("Regrid() values = %v, %v", got.Values["53394500"], got.Values["53394590"])
+	}
+}
+
+func TestRegrid_Integrated(t *testing.T) {
+	// 5倍地域メッシュ(約5km)を 2倍地域メッシュ(約2km)に変換すると、境界が一致しない
+	values := map[MeshCode]float64{"5339451": 25}
+	got, err := Regrid(values, LevelTwofold, RegridSum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum float64
+	for _, v := range got.Values {
+		sum += v
+	}
+	if len(got.Values) != 9 || math.Abs(sum-25) > 1e-9 {
+		t.Errorf("Regrid() values = %v", got.Values)
+	}
+	// 南西端の2倍地域メッシュは全体が含まれ、北東端は4分の1が含まれる
+	if got.Coverage["533945005"] != 1 || math.Abs(got.Coverage["533945445"]-0.25) > 1e-3 {
+		t.Errorf("Regrid() coverage = %v", got.Coverage)
+	}
+	if len(got.Partial) != 5 {
+		t.Errorf("Regrid() partial = %v", got.Partial)
+	}
+
+	// 密度として変換すると値は変わらない
+	got, err = Regrid(map[MeshCode]float64{"5339451": 3, "5339452": 3}, LevelTwofold, RegridMean)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for code, v := range got.Values {
+		if math.Abs(v-3) > 1e-9 {
+			t.Errorf("Regrid() values[%v] = %v, want %v", code, v, 3)
+		}
+	}
+}
+
+func TestRegrid_Error(t *testing.T) {
+	if _, err := Regrid(map[MeshCode]float64{"533945": 1}, "4", RegridSum); err != ErrInvalidLevel {
+		t.Errorf("Regrid() error = %v, wantErr %v", err, ErrInvalidLevel)
+	}
+	if _, err := Regrid(map[MeshCode]float64{"1": 1}, Level3, RegridSum); err != ErrInvalidMeshCode {
+		t.Errorf("Regrid() error = %v, wantErr %v", err, ErrInvalidMeshCode)
+	}
+}
@@ -0,0 +1,340 @@
+package japanmesh
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+var ErrInvalidRenderOptions = errors.New("invalid render options")
+
+// ColorRamp 値の最小から最大までを等間隔に割り当てた色。間の値は線形補間する。
+type ColorRamp []color.RGBA
+
+var (
+	// RampYellowRed 黄から赤
+	RampYellowRed = ColorRamp{{255, 255, 178, 255}, {254, 204, 92, 255}, {253, 141, 60, 255}, {240, 59, 32, 255}, {189, 0, 38, 255}}
+	// RampViridis 紫から黄
+	RampViridis = ColorRamp{{68, 1, 84, 255}, {59, 82, 139, 255}, {33, 145, 140, 255}, {94, 201, 98, 255}, {253, 231, 37, 255}}
+	// RampBlueRed 青から白を経て赤(正負のある値向け)
+	RampBlueRed = ColorRamp{{33, 102, 172, 255}, {146, 197, 222, 255}, {247, 247, 247, 255}, {244, 165, 130, 255}, {178, 24, 43, 255}}
+)
+
+// renderMaxPixels 描画できる画素数の上限
+const renderMaxPixels = 1 << 26
+
+// 凡例の大きさ(画素)
+const (
+	legendHeight    = 24
+	legendBarHeight = 8
+	legendMargin    = 4
+	legendMaxWidth  = 256
+)
+
+// RenderOptions 地域メッシュの値を描画する方法
+type RenderOptions struct {
+	// 描画する範囲。ゼロ値の場合は values のメッシュ全体を含む範囲
+	Bounds Bounds
+	// 1画素の緯度経度の間隔。ゼロ値の場合は values のうち最も細かいレベルの間隔
+	PixelSize Distance
+	// 色。nil の場合は RampYellowRed
+	Ramp ColorRamp
+	// 色を割り当てる値の範囲。Min と Max が等しい場合は values の最小値と最大値
+	Min float64
+	Max float64
+	// 地図の下に凡例を描画する。
+	Legend bool
+	// 第1次地域区画の境界線を描画する。
+	Level1Grid bool
+	// 背景色。nil の場合は透明
+	Background color.Color
+}
+
+// Color 値に対応する色を取得する。
+func (r ColorRamp) Color(v, min, max float64) color.RGBA {
+	if len(r) == 0 {
+		return color.RGBA{}
+	}
+	t := 0.0
+	if max > min {
+		t = math.Max(0, math.Min(1, (v-min)/(max-min)))
+	}
+	pos := t * float64(len(r)-1)
+	i := int(pos)
+	if i >= len(r)-1 {
+		return r[len(r)-1]
+	}
+	f := pos - float64(i)
+	lerp := func(a, b uint8) uint8 { return uint8(math.Round(float64(a) + (float64(b)-float64(a))*f)) }
+	return color.RGBA{lerp(r[i].R, r[i+1].R), lerp(r[i].G, r[i+1].G), lerp(r[i].B, r[i+1].B), lerp(r[i].A, r[i+1].A)}
+}
+
+// renderLayout 描画する地図の大きさと、メッシュの描画順
+type renderLayout struct {
+	opts   RenderOptions
+	width  int
+	height int
+	codes  MeshCodes
+	bounds map[MeshCode]Bounds
+}
+
+// RenderPNG 地域メッシュの値を色分けした PNG 画像を書き出す。
+// 異なるレベルのメッシュが混在する場合は、細かいメッシュを上に描画する。
+func RenderPNG(w io.Writer, values map[MeshCode]float64, opts RenderOptions) error {
+	l, err := newRenderLayout(values, opts)
+	if err != nil {
+		return err
+	}
+	height := l.height
+	if l.opts.Legend {
+		height += legendHeight
+	}
+	img := image.NewRGBA(image.Rect(0, 0, l.width, height))
+	if l.opts.Background != nil {
+		draw.Draw(img, img.Bounds(), image.NewUniform(l.opts.Background), image.Point{}, draw.Src)
+	}
+	mapArea := image.Rect(0, 0, l.width, l.height)
+	for _, code := range l.codes {
+		x0, y0, x1, y1 := l.rect(l.bounds[code])
+		r := image.Rect(int(math.Round(x0)), int(math.Round(y0)), int(math.Round(x1)), int(math.Round(y1)))
+		// 画素より小さいメッシュも1画素で描画する
+		if r.Dx() == 0 {
+			r.Max.X++
+		}
+		if r.Dy() == 0 {
+			r.Max.Y++
+		}
+		c := l.opts.Ramp.Color(values[code], l.opts.Min, l.opts.Max)
+		draw.Draw(img, r.Intersect(mapArea), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	if l.opts.Level1Grid {
+		gray := color.RGBA{96, 96, 96, 255}
+		for _, x := range l.level1Lines(false) {
+			draw.Draw(img, image.Rect(int(math.Round(x)), 0, int(math.Round(x))+1, l.height).Intersect(mapArea), image.NewUniform(gray), image.Point{}, draw.Src)
+		}
+		for _, y := range l.level1Lines(true) {
+			draw.Draw(img, image.Rect(0, int(math.Round(y)), l.width, int(math.Round(y))+1).Intersect(mapArea), image.NewUniform(gray), image.Point{}, draw.Src)
+		}
+	}
+	if l.opts.Legend {
+		top := l.height + legendMargin
+		barWidth := l.legendWidth()
+		for x := 0; x < barWidth; x++ {
+			c := l.opts.Ramp.Color(float64(x), 0, float64(barWidth-1))
+			draw.Draw(img, image.Rect(legendMargin+x, top, legendMargin+x+1, top+legendBarHeight), image.NewUniform(c), image.Point{}, draw.Src)
+		}
+		black := color.RGBA{0, 0, 0, 255}
+		minLabel, maxLabel := formatLegendValue(l.opts.Min), formatLegendValue(l.opts.Max)
+		drawText(img, legendMargin, top+legendBarHeight+2, minLabel, black)
+		drawText(img, legendMargin+barWidth-textWidth(maxLabel), top+legendBarHeight+2, maxLabel, black)
+	}
+	return png.Encode(w, img)
+}
+
+// RenderSVG 地域メッシュの値を色分けした SVG 画像を書き出す。座標の単位は RenderPNG の画素と等しい。
+func RenderSVG(w io.Writer, values map[MeshCode]float64, opts RenderOptions) error {
+	l, err := newRenderLayout(values, opts)
+	if err != nil {
+		return err
+	}
+	height := l.height
+	if l.opts.Legend {
+		height += legendHeight
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", l.width, height, l.width, height)
+	if l.opts.Background != nil {
+		fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgColor(l.opts.Background))
+	}
+	for _, code := range l.codes {
+		x0, y0, x1, y1 := l.rect(l.bounds[code])
+		c := l.opts.Ramp.Color(values[code], l.opts.Min, l.opts.Max)
+		fmt.Fprintf(bw, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"><title>%s: %s</title></rect>`+"\n",
+			svgNumber(x0), svgNumber(y0), svgNumber(x1-x0), svgNumber(y1-y0), svgColor(c), code, strconv.FormatFloat(values[code], 'g', -1, 64))
+	}
+	if l.opts.Level1Grid {
+		bw.WriteString(`<path fill="none" stroke="#606060" stroke-width="1" d="`)
+		for _, x := range l.level1Lines(false) {
+			fmt.Fprintf(bw, "M%s 0V%d", svgNumber(x), l.height)
+		}
+		for _, y := range l.level1Lines(true) {
+			fmt.Fprintf(bw, "M0 %sH%d", svgNumber(y), l.width)
+		}
+		bw.WriteString("\"/>\n")
+	}
+	if l.opts.Legend {
+		bw.WriteString(`<defs><linearGradient id="legend">`)
+		for i, c := range l.opts.Ramp {
+			offset := 0.0
+			if len(l.opts.Ramp) > 1 {
+				offset = float64(i) / float64(len(l.opts.Ramp)-1)
+			}
+			fmt.Fprintf(bw, `<stop offset="%s" stop-color="%s"/>`, svgNumber(offset), svgColor(c))
+		}
+		bw.WriteString("</linearGradient></defs>\n")
+		top := l.height + legendMargin
+		barWidth := l.legendWidth()
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="url(#legend)"/>`+"\n", legendMargin, top, barWidth, legendBarHeight)
+		fmt.Fprintf(bw, `<text x="%d" y="%d" font-size="8" font-family="sans-serif">%s</text>`+"\n", legendMargin, top+legendBarHeight+8, formatLegendValue(l.opts.Min))
+		fmt.Fprintf(bw, `<text x="%d" y="%d" font-size="8" font-family="sans-serif" text-anchor="end">%s</text>`+"\n", legendMargin+barWidth, top+legendBarHeight+8, formatLegendValue(l.opts.Max))
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// newRenderLayout 省略された描画の設定を values から求める。
+func newRenderLayout(values map[MeshCode]float64, opts RenderOptions) (*renderLayout, error) {
+	l := &renderLayout{bounds: make(map[MeshCode]Bounds, len(values))}
+	var extent Bounds
+	finest := 0
+	var pixelSize Distance
+	min, max := math.Inf(1), math.Inf(-1)
+	for code, v := range values {
+		c, err := toCell(code)
+		if err != nil {
+			return nil, err
+		}
+		b := c.bounds()
+		if len(l.codes) == 0 {
+			extent = b
+		} else {
+			extent.Min.Latitude, extent.Min.Longitude = math.Min(extent.Min.Latitude, b.Min.Latitude), math.Min(extent.Min.Longitude, b.Min.Longitude)
+			extent.Max.Latitude, extent.Max.Longitude = math.Max(extent.Max.Latitude, b.Max.Latitude), math.Max(extent.Max.Longitude, b.Max.Longitude)
+		}
+		if n := getCellCount(c.level); n > finest {
+			finest = n
+			mesh, _ := getMesh(c.level)
+			pixelSize = mesh.Distance
+		}
+		if !math.IsNaN(v) {
+			min, max = math.Min(min, v), math.Max(max, v)
+		}
+		l.codes = append(l.codes, code)
+		l.bounds[code] = b
+	}
+
+	if opts.Bounds == (Bounds{}) {
+		opts.Bounds = extent
+	}
+	if opts.PixelSize == (Distance{}) {
+		opts.PixelSize = pixelSize
+	}
+	if opts.Ramp == nil {
+		opts.Ramp = RampYellowRed
+	}
+	if opts.Min == opts.Max && !math.IsInf(min, 1) {
+		opts.Min, opts.Max = min, max
+	}
+	l.opts = opts
+	if !(opts.PixelSize.Lat > 0 && opts.PixelSize.Lng > 0) || !(opts.Bounds.Max.Latitude > opts.Bounds.Min.Latitude && opts.Bounds.Max.Longitude > opts.Bounds.Min.Longitude) {
+		return nil, ErrInvalidRenderOptions
+	}
+	w := math.Ceil((opts.Bounds.Max.Longitude-opts.Bounds.Min.Longitude)/opts.PixelSize.Lng - 1e-9)
+	h := math.Ceil((opts.Bounds.Max.Latitude-opts.Bounds.Min.Latitude)/opts.PixelSize.Lat - 1e-9)
+	if w*h > renderMaxPixels {
+		return nil, ErrInvalidRenderOptions
+	}
+	l.width, l.height = int(w), int(h)
+
+	// 粗いメッシュから順に描画し、同じレベルではメッシュコードの昇順とする
+	sort.Slice(l.codes, func(i, j int) bool {
+		li, _ := GetLevel(l.codes[i])
+		lj, _ := GetLevel(l.codes[j])
+		if ni, nj := getCellCount(li), getCellCount(lj); ni != nj {
+			return ni < nj
+		}
+		return l.codes[i] < l.codes[j]
+	})
+	// NaN のメッシュは描画しない
+	codes := l.codes[:0]
+	for _, code := range l.codes {
+		if !math.IsNaN(values[code]) {
+			codes = append(codes, code)
+		}
+	}
+	l.codes = codes
+	return l, nil
+}
+
+// rect 範囲を画素の座標に変換する。
+func (l *renderLayout) rect(b Bounds) (x0, y0, x1, y1 float64) {
+	x0 = (b.Min.Longitude - l.opts.Bounds.Min.Longitude) / l.opts.PixelSize.Lng
+	x1 = (b.Max.Longitude - l.opts.Bounds.Min.Longitude) / l.opts.PixelSize.Lng
+	y0 = (l.opts.Bounds.Max.Latitude - b.Max.Latitude) / l.opts.PixelSize.Lat
+	y1 = (l.opts.Bounds.Max.Latitude - b.Min.Latitude) / l.opts.PixelSize.Lat
+	return x0, y0, x1, y1
+}
+
+// level1Lines 描画する範囲にある第1次地域区画の境界線の座標を取得する。
+// horizontal が true の場合は緯線の y 座標、false の場合は経線の x 座標を返す。
+func (l *renderLayout) level1Lines(horizontal bool) []float64 {
+	b := l.opts.Bounds
+	var lines []float64
+	if horizontal {
+		for y := math.Ceil(b.Min.Latitude / level1Mesh.Distance.Lat); y*level1Mesh.Distance.Lat <= b.Max.Latitude; y++ {
+			lines = append(lines, (b.Max.Latitude-y*level1Mesh.Distance.Lat)/l.opts.PixelSize.Lat)
+		}
+		return lines
+	}
+	for x := math.Ceil(b.Min.Longitude); x <= b.Max.Longitude; x++ {
+		lines = append(lines, (x-b.Min.Longitude)/l.opts.PixelSize.Lng)
+	}
+	return lines
+}
+
+func (l *renderLayout) legendWidth() int {
+	return maxInt(1, minInt(legendMaxWidth, l.width-legendMargin*2))
+}
+
+func formatLegendValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func svgColor(c color.Color) string {
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	if rgba.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", rgba.R, rgba.G, rgba.B)
+	}
+	return fmt.Sprintf("rgba(%d,%d,%d,%s)", rgba.R, rgba.G, rgba.B, strconv.FormatFloat(float64(rgba.A)/255, 'f', 3, 64))
+}
+
+// glyphs 凡例の数値を描画する3×5画素の字形(各行の下位3ビット)
+var glyphs = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7}, '1': {2, 6, 2, 2, 7}, '2': {7, 1, 7, 4, 7}, '3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1}, '5': {7, 4, 7, 1, 7}, '6': {7, 4, 7, 5, 7}, '7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7}, '9': {7, 5, 7, 1, 7}, '-': {0, 0, 7, 0, 0}, '.': {0, 0, 0, 0, 2},
+	'+': {0, 2, 7, 2, 0}, 'e': {0, 7, 7, 4, 7}, 'I': {7, 2, 2, 2, 7}, 'n': {0, 6, 5, 5, 5},
+	'f': {3, 4, 7, 4, 4}, 'N': {5, 7, 7, 7, 5}, 'a': {0, 7, 1, 7, 7},
+}
+
+// textWidth 文字列を描画した幅(画素)
+func textWidth(s string) int {
+	return len(s) * 4
+}
+
+// drawText 3×5画素の字形で文字列を描画する。
+func drawText(img draw.Image, x, y int, s string, c color.Color) {
+	for _, r := range s {
+		glyph := glyphs[r]
+		for row, bits := range glyph {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) != 0 {
+					img.Set(x+col, y+row, c)
+				}
+			}
+		}
+		x += 4
+	}
+}
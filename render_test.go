@@ -0,0 +1,140 @@
+package japanmesh
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestColorRamp_Color(t *testing.T) {
+	ramp := ColorRamp{{0, 0, 0, 255}, {200, 100, 50, 255}}
+	tests := []struct {
+		name string
+		v    float64
+		want color.RGBA
+	}{
+		{name: "min", v: 0, want: color.RGBA{0, 0, 0, 255}},
+		{name: "max", v: 10, want: color.RGBA{200, 100, 50, 255}},
+		{name: "middle", v: 5, want: color.RGBA{100, 50, 25, 255}},
+		{name: "below", v: -1, want: color.RGBA{0, 0, 0, 255}},
+		{name: "above", v: 20, want: color.RGBA{200, 100, 50, 255}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ramp.Color(tt.v, 0, 10); got != tt.want {
+				t.Errorf("ColorRamp.Color() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	values := map[MeshCode]float64{"53394547": 0, "53394548": 10, "53394557": 5}
+	var buf bytes.Buffer
+	if err := RenderPNG(&buf, values, RenderOptions{}); err != nil {
+		t.Fatalf("RenderPNG() error = %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if got := img.Bounds().Size(); got.X != 2 || got.Y != 2 {
+		t.Errorf("RenderPNG() size = %v, want 2x2", got)
+	}
+	tests := []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{x: 0, y: 1, want: RampYellowRed[0]},
+		{x: 1, y: 1, want: RampYellowRed[4]},
+		{x: 0, y: 0, want: RampYellowRed[2]},
+		// 値のないメッシュは透明
+		{x: 1, y: 0, want: color.RGBA{}},
+	}
+	for _, tt := range tests {
+		if got := color.RGBAModel.Convert(img.At(tt.x, tt.y)); got != tt.want {
+			t.Errorf("RenderPNG() at (%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestRenderPNG_Options(t *testing.T) {
+	// 5339 と 5340 の境界をまたぐ第2次地域区画
+	values := map[MeshCode]float64{"533977": 1, "534070": 2}
+	var buf bytes.Buffer
+	opts := RenderOptions{
+		PixelSize:  Distance{Lat: level2Mesh.Distance.Lat / 4, Lng: level2Mesh.Distance.Lng / 4},
+		Legend:     true,
+		Level1Grid: true,
+		Background: color.White,
+	}
+	if err := RenderPNG(&buf, values, opts); err != nil {
+		t.Fatalf("RenderPNG() error = %v", err)
+	}
+	img, _ := png.Decode(&buf)
+	if got := img.Bounds().Size(); got.X != 8 || got.Y != 4+legendHeight {
+		t.Errorf("RenderPNG() size = %v, want 8x%d", got, 4+legendHeight)
+	}
+	gray := color.RGBA{96, 96, 96, 255}
+	if got := color.RGBAModel.Convert(img.At(4, 2)); got != gray {
+		t.Errorf("RenderPNG() level1 grid = %v, want %v", got, gray)
+	}
+	if got := color.RGBAModel.Convert(img.At(1, 2)); got != RampYellowRed[0] {
+		t.Errorf("RenderPNG() mesh = %v, want %v", got, RampYellowRed[0])
+	}
+	if got := color.RGBAModel.Convert(img.At(legendMargin, 4+legendMargin)); got != RampYellowRed[0] {
+		t.Errorf("RenderPNG() legend = %v, want %v", got, RampYellowRed[0])
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	values := map[MeshCode]float64{"53394547": 0, "53394548": 10, "533945": 3}
+	var buf bytes.Buffer
+	if err := RenderSVG(&buf, values, RenderOptions{Legend: true, Level1Grid: true}); err != nil {
+		t.Fatalf("RenderSVG() error = %v", err)
+	}
+	s := buf.String()
+	// 粗いメッシュを先に描画する
+	if strings.Index(s, "533945: 3") > strings.Index(s, "53394547: 0") {
+		t.Errorf("RenderSVG() draws finer mesh first")
+	}
+	if !strings.Contains(s, `fill="#ffffb2"`) || !strings.Contains(s, "linearGradient") {
+		t.Errorf("RenderSVG() got = %v", s)
+	}
+	dec := xml.NewDecoder(strings.NewReader(s))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("RenderSVG() invalid xml: %v", err)
+		}
+	}
+}
+
+func TestRender_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[MeshCode]float64
+		opts    RenderOptions
+		wantErr error
+	}{
+		{name: "empty", values: nil, opts: RenderOptions{}, wantErr: ErrInvalidRenderOptions},
+		{name: "invalid meshcode", values: map[MeshCode]float64{"53394": 1}, opts: RenderOptions{}, wantErr: ErrInvalidMeshCode},
+		{name: "too large", values: map[MeshCode]float64{"5339": 1}, opts: RenderOptions{PixelSize: Distance{Lat: 1e-6, Lng: 1e-6}}, wantErr: ErrInvalidRenderOptions},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RenderPNG(io.Discard, tt.values, tt.opts); !errors.Is(err, tt.wantErr) {
+				t.Errorf("RenderPNG() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := RenderSVG(io.Discard, tt.values, tt.opts); !errors.Is(err, tt.wantErr) {
+				t.Errorf("RenderSVG() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package japanmesh
+
+import (
+	"math"
+	"sort"
+)
+
+// Reducer 同じ上位のメッシュに含まれるメッシュの値をまとめる関数
+type Reducer func(codes MeshCodes, values []float64) float64
+
+// Distributor 上位のメッシュの値を、配下のメッシュに配分する関数。children と同じ順序で値を返す。
+type Distributor func(code MeshCode, value float64, children MeshCodes) []float64
+
+// ReduceSum 合計
+func ReduceSum(_ MeshCodes, values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+// ReduceMean 平均
+func ReduceMean(codes MeshCodes, values []float64) float64 {
+	return ReduceSum(codes, values) / float64(len(values))
+}
+
+// ReduceMax 最大値
+func ReduceMax(_ MeshCodes, values []float64) float64 {
+	max := math.Inf(-1)
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	return max
+}
+
+// ReduceMin 最小値
+func ReduceMin(_ MeshCodes, values []float64) float64 {
+	min := math.Inf(1)
+	for _, v := range values {
+		min = math.Min(min, v)
+	}
+	return min
+}
+
+// ReduceAreaWeightedMean 各メッシュの面積(GetArea)で重み付けした平均
+func ReduceAreaWeightedMean(codes MeshCodes, values []float64) float64 {
+	var sum, total float64
+	for i, code := range codes {
+		area, err := GetArea(code)
+		if err != nil {
+			continue
+		}
+		sum += values[i] * area
+		total += area
+	}
+	return sum / total
+}
+
+// DistributeUniform 配下のメッシュに均等に配分する。
+func DistributeUniform(_ MeshCode, value float64, children MeshCodes) []float64 {
+	values := make([]float64, len(children))
+	for i := range values {
+		values[i] = value / float64(len(children))
+	}
+	return values
+}
+
+// DistributeCopy 配下のメッシュに同じ値を設定する(密度や割合など、面積に依存しない値向け)。
+func DistributeCopy(_ MeshCode, value float64, children MeshCodes) []float64 {
+	values := make([]float64, len(children))
+	for i := range values {
+		values[i] = value
+	}
+	return values
+}
+
+// DistributeWeighted 配下のメッシュに weights の値に比例して配分する。
+// weights に含まれないメッシュの重みは0とし、重みの合計が0の場合は均等に配分する。
+func DistributeWeighted(weights map[MeshCode]float64) Distributor {
+	return func(code MeshCode, value float64, children MeshCodes) []float64 {
+		var total float64
+		for _, child := range children {
+			total += weights[child]
+		}
+		if total == 0 {
+			return DistributeUniform(code, value, children)
+		}
+		values := make([]float64, len(children))
+		for i, child := range children {
+			values[i] = value * weights[child] / total
+		}
+		return values
+	}
+}
+
+// RollUp 地域メッシュごとの値を、指定した上位のレベルのメッシュごとに reducer でまとめる。
+// values に指定したレベルの配下にないメッシュが含まれる場合は ErrInvalidLevel を返す。
+// 統合地域メッシュへの変換には Regrid を使う。
+func RollUp(values map[MeshCode]float64, level Level, reducer Reducer) (map[MeshCode]float64, error) {
+	if _, ok := getMesh(level); !ok {
+		return nil, ErrInvalidLevel
+	}
+	groups := make(map[MeshCode]MeshCodes)
+	for code := range values {
+		if err := Validate(code); err != nil {
+			return nil, err
+		}
+		if l, _ := GetLevel(code); !isAncestorLevel(level, l) {
+			return nil, ErrInvalidLevel
+		}
+		parent := getCodeByLevel(code, level)
+		groups[parent] = append(groups[parent], code)
+	}
+
+	results := make(map[MeshCode]float64, len(groups))
+	for parent, codes := range groups {
+		// 浮動小数点の演算順序を一定にするため並べ替える
+		sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+		vs := make([]float64, len(codes))
+		for i, code := range codes {
+			vs[i] = values[code]
+		}
+		results[parent] = reducer(codes, vs)
+	}
+	return results, nil
+}
+
+// DrillDown 地域メッシュごとの値を、指定した下位のレベルのメッシュに distributor で配分する。
+// values に指定したレベルの上位にないメッシュが含まれる場合は ErrInvalidLevel を返す。
+// 統合地域メッシュへの変換には Regrid を使う。
+func DrillDown(values map[MeshCode]float64, level Level, distributor Distributor) (map[MeshCode]float64, error) {
+	if _, ok := getMesh(level); !ok {
+		return nil, ErrInvalidLevel
+	}
+	results := make(map[MeshCode]float64)
+	for code, value := range values {
+		if err := Validate(code); err != nil {
+			return nil, err
+		}
+		children, err := getDescendants(code, level)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range distributor(code, value, children) {
+			results[children[i]] += v
+		}
+	}
+	return results, nil
+}
+
+// getDescendants 指定したレベルの配下の地域メッシュコードを GetCodes でたどって取得する。
+func getDescendants(code MeshCode, level Level) (MeshCodes, error) {
+	mesh, ok := getMesh(level)
+	if !ok || !isStandardLevel(level) {
+		return nil, ErrInvalidLevel
+	}
+	if l, _ := GetLevel(code); !isAncestorLevel(l, level) {
+		return nil, ErrInvalidLevel
+	}
+	codes := MeshCodes{code}
+	for codes[0].getDigit() < mesh.Digit {
+		var children MeshCodes
+		for _, c := range codes {
+			cs, err := GetCodes(c)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, cs...)
+		}
+		codes = children
+	}
+	return codes, nil
+}
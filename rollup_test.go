@@ -0,0 +1,116 @@
+package japanmesh
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRollUp(t *testing.T) {
+	values := map[MeshCode]float64{
+		"533945471": 1,
+		"533945472": 2,
+		"533945473": 3,
+		"533945474": 6,
+		"533945481": 10,
+	}
+	tests := []struct {
+		name    string
+		level   Level
+		reducer Reducer
+		want    map[MeshCode]float64
+		wantErr bool
+	}{
+		{name: "sum", level: Level3, reducer: ReduceSum, want: map[MeshCode]float64{"53394547": 12, "53394548": 10}},
+		{name: "mean", level: Level3, reducer: ReduceMean, want: map[MeshCode]float64{"53394547": 3, "53394548": 10}},
+		{name: "max", level: Level2, reducer: ReduceMax, want: map[MeshCode]float64{"533945": 10}},
+		{name: "min", level: Level1, reducer: ReduceMin, want: map[MeshCode]float64{"5339": 1}},
+		{name: "same level", level: LevelHalf, reducer: ReduceSum, want: values},
+		{name: "finer level", level: LevelQuarter, reducer: ReduceSum, want: nil, wantErr: true},
+		{name: "invalid level", level: "4", reducer: ReduceSum, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RollUp(values, tt.level, tt.reducer)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RollUp() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RollUp() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollUp_AreaWeightedMean(t *testing.T) {
+	// 南北に並ぶメッシュは北側ほど面積が小さい
+	values := map[MeshCode]float64{"533900": 0, "533970": 100}
+	got, err := RollUp(values, Level1, ReduceAreaWeightedMean)
+	if err != nil {
+		t.Fatal(err)
+	}
+	south, _ := GetArea("533900")
+	north, _ := GetArea("533970")
+	want := 100 * north / (south + north)
+	if math.Abs(got["5339"]-want) > 1e-9 || got["5339"] >= 50 {
+		t.Errorf("RollUp() got = %v, want %v", got["5339"], want)
+	}
+}
+
+func TestDrillDown(t *testing.T) {
+	values := map[MeshCode]float64{"53394547": 100}
+	tests := []struct {
+		name        string
+		level       Level
+		distributor Distributor
+		want        map[MeshCode]float64
+		wantErr     bool
+	}{
+		{
+			name:        "uniform",
+			level:       LevelHalf,
+			distributor: DistributeUniform,
+			want:        map[MeshCode]float64{"533945471": 25, "533945472": 25, "533945473": 25, "533945474": 25},
+		},
+		{
+			name:        "copy",
+			level:       LevelHalf,
+			distributor: DistributeCopy,
+			want:        map[MeshCode]float64{"533945471": 100, "533945472": 100, "533945473": 100, "533945474": 100},
+		},
+		{
+			name:        "weighted",
+			level:       LevelHalf,
+			distributor: DistributeWeighted(map[MeshCode]float64{"533945471": 3, "533945474": 1}),
+			want:        map[MeshCode]float64{"533945471": 75, "533945472": 0, "533945473": 0, "533945474": 25},
+		},
+		{
+			name:        "weighted without weights",
+			level:       LevelHalf,
+			distributor: DistributeWeighted(nil),
+			want:        map[MeshCode]float64{"533945471": 25, "533945472": 25, "533945473": 25, "533945474": 25},
+		},
+		{name: "coarser level", level: Level2, distributor: DistributeUniform, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DrillDown(values, tt.level, tt.distributor)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DrillDown() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DrillDown() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	got, err := DrillDown(values, LevelQuarter, DistributeUniform)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 16 || got["5339454714"] != 6.25 {
+		t.Errorf("DrillDown() got = %v", got)
+	}
+}
@@ -0,0 +1,175 @@
+package server
+
+import "math"
+
+// Mapbox Vector Tile (https://github.com/mapbox/vector-tile-spec/tree/master/2.1) の Protocol Buffers 形式を書き出す。
+
+// mvtExtent タイルの1辺の座標の数
+const mvtExtent = 4096
+
+// 図形の種類
+const (
+	mvtLineString uint32 = 2
+	mvtPolygon    uint32 = 3
+)
+
+// 図形の命令
+const (
+	mvtMoveTo    = 1
+	mvtLineTo    = 2
+	mvtClosePath = 7
+)
+
+// Protocol Buffers のワイヤタイプ
+const (
+	wireVarint = 0
+	wire64Bit  = 1
+	wireBytes  = 2
+)
+
+// mvtLayer タイルのレイヤー。属性の名前と値は重複を除いてレイヤーごとに保持する。
+type mvtLayer struct {
+	name       string
+	features   [][]byte
+	keys       []string
+	keyIndex   map[string]uint32
+	values     []interface{}
+	valueIndex map[interface{}]uint32
+}
+
+// mvtProperty 地物の属性。値は string または float64 とする。
+type mvtProperty struct {
+	key   string
+	value interface{}
+}
+
+func newMVTLayer(name string) *mvtLayer {
+	return &mvtLayer{name: name, keyIndex: map[string]uint32{}, valueIndex: map[interface{}]uint32{}}
+}
+
+// addFeature 地物を追加する。geometry は mvtGeometry で組み立てた命令列とする。
+func (l *mvtLayer) addFeature(geomType uint32, geometry []uint32, properties ...mvtProperty) {
+	tags := make([]uint32, 0, 2*len(properties))
+	for _, p := range properties {
+		k, ok := l.keyIndex[p.key]
+		if !ok {
+			k = uint32(len(l.keys))
+			l.keys = append(l.keys, p.key)
+			l.keyIndex[p.key] = k
+		}
+		v, ok := l.valueIndex[p.value]
+		if !ok {
+			v = uint32(len(l.values))
+			l.values = append(l.values, p.value)
+			l.valueIndex[p.value] = v
+		}
+		tags = append(tags, k, v)
+	}
+	var f []byte
+	if len(tags) > 0 {
+		f = appendPacked(f, 2, tags)
+	}
+	f = appendTag(f, 3, wireVarint)
+	f = appendVarint(f, uint64(geomType))
+	f = appendPacked(f, 4, geometry)
+	l.features = append(l.features, f)
+}
+
+// marshal レイヤーを書き出す。
+func (l *mvtLayer) marshal() []byte {
+	var b []byte
+	b = appendTag(b, 15, wireVarint)
+	b = appendVarint(b, 2)
+	b = appendBytes(b, 1, []byte(l.name))
+	for _, f := range l.features {
+		b = appendBytes(b, 2, f)
+	}
+	for _, k := range l.keys {
+		b = appendBytes(b, 3, []byte(k))
+	}
+	for _, v := range l.values {
+		var value []byte
+		switch v := v.(type) {
+		case string:
+			value = appendBytes(value, 1, []byte(v))
+		case float64:
+			value = appendTag(value, 3, wire64Bit)
+			bits := math.Float64bits(v)
+			for i := 0; i < 8; i++ {
+				value = append(value, byte(bits>>(8*i)))
+			}
+		}
+		b = appendBytes(b, 4, value)
+	}
+	b = appendTag(b, 5, wireVarint)
+	b = appendVarint(b, mvtExtent)
+	return b
+}
+
+// marshalMVT 地物のあるレイヤーのみをタイルとして書き出す。
+func marshalMVT(layers ...*mvtLayer) []byte {
+	var b []byte
+	for _, l := range layers {
+		if len(l.features) > 0 {
+			b = appendBytes(b, 3, l.marshal())
+		}
+	}
+	return b
+}
+
+// mvtGeometry 図形の命令列。座標は直前の点からの差分で表す。
+type mvtGeometry struct {
+	commands []uint32
+	x, y     int
+}
+
+func (g *mvtGeometry) moveTo(x, y int) {
+	g.commands = append(g.commands, mvtMoveTo|1<<3)
+	g.point(x, y)
+}
+
+func (g *mvtGeometry) lineTo(points ...[2]int) {
+	g.commands = append(g.commands, mvtLineTo|uint32(len(points))<<3)
+	for _, p := range points {
+		g.point(p[0], p[1])
+	}
+}
+
+func (g *mvtGeometry) closePath() {
+	g.commands = append(g.commands, mvtClosePath|1<<3)
+}
+
+func (g *mvtGeometry) point(x, y int) {
+	g.commands = append(g.commands, zigzag(x-g.x), zigzag(y-g.y))
+	g.x, g.y = x, y
+}
+
+func zigzag(n int) uint32 {
+	return uint32(int32(n)<<1 ^ int32(n)>>31)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendTag(b []byte, field, wireType int) []byte {
+	return appendVarint(b, uint64(field<<3|wireType))
+}
+
+func appendBytes(b []byte, field int, v []byte) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = appendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendPacked(b []byte, field int, values []uint32) []byte {
+	var packed []byte
+	for _, v := range values {
+		packed = appendVarint(packed, uint64(v))
+	}
+	return appendBytes(b, field, packed)
+}
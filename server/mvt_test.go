@@ -0,0 +1,197 @@
+package server
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// decodedLayer テストで読み込んだレイヤー
+type decodedLayer struct {
+	version  uint64
+	name     string
+	extent   uint64
+	keys     []string
+	values   []interface{}
+	features []decodedFeature
+}
+
+type decodedFeature struct {
+	geomType uint64
+	tags     []uint64
+	geometry []uint64
+}
+
+// properties 地物の属性
+func (l decodedLayer) properties(f decodedFeature) map[string]interface{} {
+	m := map[string]interface{}{}
+	for i := 0; i+1 < len(f.tags); i += 2 {
+		m[l.keys[f.tags[i]]] = l.values[f.tags[i+1]]
+	}
+	return m
+}
+
+// readFields Protocol Buffers のメッセージをフィールドごとに読み込む。
+func readFields(t *testing.T, b []byte, fn func(field int, wireType int, v uint64, data []byte)) {
+	t.Helper()
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatalf("invalid key")
+		}
+		b = b[n:]
+		field, wireType := int(key>>3), int(key&7)
+		switch wireType {
+		case wireVarint:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				t.Fatalf("invalid varint")
+			}
+			b = b[n:]
+			fn(field, wireType, v, nil)
+		case wire64Bit:
+			fn(field, wireType, binary.LittleEndian.Uint64(b), nil)
+			b = b[8:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || int(l) > len(b)-n {
+				t.Fatalf("invalid length")
+			}
+			fn(field, wireType, 0, b[n:n+int(l)])
+			b = b[n+int(l):]
+		default:
+			t.Fatalf("unexpected wire type %d", wireType)
+		}
+	}
+}
+
+func readPacked(t *testing.T, b []byte) []uint64 {
+	var values []uint64
+	for len(b) > 0 {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatalf("invalid packed varint")
+		}
+		values = append(values, v)
+		b = b[n:]
+	}
+	return values
+}
+
+// decodeMVT ベクトルタイルをレイヤーの名前ごとに読み込む。
+func decodeMVT(t *testing.T, b []byte) map[string]decodedLayer {
+	t.Helper()
+	layers := map[string]decodedLayer{}
+	readFields(t, b, func(field, _ int, _ uint64, data []byte) {
+		if field != 3 {
+			t.Fatalf("unexpected tile field %d", field)
+		}
+		var l decodedLayer
+		readFields(t, data, func(field, wireType int, v uint64, data []byte) {
+			switch field {
+			case 15:
+				l.version = v
+			case 1:
+				l.name = string(data)
+			case 5:
+				l.extent = v
+			case 3:
+				l.keys = append(l.keys, string(data))
+			case 4:
+				readFields(t, data, func(field, _ int, v uint64, data []byte) {
+					switch field {
+					case 1:
+						l.values = append(l.values, string(data))
+					case 3:
+						l.values = append(l.values, math.Float64frombits(v))
+					}
+				})
+			case 2:
+				var f decodedFeature
+				readFields(t, data, func(field, _ int, v uint64, data []byte) {
+					switch field {
+					case 2:
+						f.tags = readPacked(t, data)
+					case 3:
+						f.geomType = v
+					case 4:
+						f.geometry = readPacked(t, data)
+					}
+				})
+				l.features = append(l.features, f)
+			}
+		})
+		layers[l.name] = l
+	})
+	return layers
+}
+
+// decodeGeometry 命令列を絶対座標の点の列に変換する。ClosePath は始点を繰り返して表す。
+func decodeGeometry(commands []uint64) [][][2]int {
+	var parts [][][2]int
+	var x, y int
+	for i := 0; i < len(commands); {
+		id, count := commands[i]&7, int(commands[i]>>3)
+		i++
+		if id == mvtClosePath {
+			last := parts[len(parts)-1]
+			parts[len(parts)-1] = append(last, last[0])
+			continue
+		}
+		for j := 0; j < count; j++ {
+			dx, dy := int64(commands[i]>>1)^-int64(commands[i]&1), int64(commands[i+1]>>1)^-int64(commands[i+1]&1)
+			i += 2
+			x, y = x+int(dx), y+int(dy)
+			if id == mvtMoveTo {
+				parts = append(parts, nil)
+			}
+			parts[len(parts)-1] = append(parts[len(parts)-1], [2]int{x, y})
+		}
+	}
+	return parts
+}
+
+func TestMarshalMVT(t *testing.T) {
+	l := newMVTLayer("test")
+	var line mvtGeometry
+	line.moveTo(10, 20)
+	line.lineTo([2]int{5, 25}, [2]int{-3, 25})
+	l.addFeature(mvtLineString, line.commands, mvtProperty{key: "name", value: "a"}, mvtProperty{key: "value", value: 1.5})
+	var polygon mvtGeometry
+	polygon.moveTo(0, 0)
+	polygon.lineTo([2]int{10, 0}, [2]int{10, 10}, [2]int{0, 10})
+	polygon.closePath()
+	l.addFeature(mvtPolygon, polygon.commands, mvtProperty{key: "name", value: "a"})
+
+	layers := decodeMVT(t, marshalMVT(l, newMVTLayer("empty")))
+	if len(layers) != 1 {
+		t.Fatalf("layers = %v, want 1", len(layers))
+	}
+	got := layers["test"]
+	if got.version != 2 || got.extent != mvtExtent || len(got.features) != 2 {
+		t.Fatalf("layer = %+v", got)
+	}
+	if !reflect.DeepEqual(got.keys, []string{"name", "value"}) || !reflect.DeepEqual(got.values, []interface{}{"a", 1.5}) {
+		t.Errorf("keys = %v, values = %v", got.keys, got.values)
+	}
+	if f := got.features[1]; !reflect.DeepEqual(f.tags, []uint64{0, 0}) || f.geomType != uint64(mvtPolygon) {
+		t.Errorf("feature = %+v", f)
+	}
+	// MoveTo 1回、(10,20)、LineTo 2回、差分 (-5,5)、(-8,0) をジグザグ符号化した値
+	if want := []uint64{9, 20, 40, 18, 9, 10, 15, 0}; !reflect.DeepEqual(got.features[0].geometry, want) {
+		t.Errorf("line geometry = %v, want %v", got.features[0].geometry, want)
+	}
+	want := [][][2]int{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	if g := decodeGeometry(got.features[1].geometry); !reflect.DeepEqual(g, want) {
+		t.Errorf("polygon = %v, want %v", g, want)
+	}
+}
+
+func TestZigzag(t *testing.T) {
+	for n, want := range map[int]uint32{0: 0, -1: 1, 1: 2, -2: 3, 2: 4, 4096: 8192, -4160: 8319} {
+		if got := zigzag(n); got != want {
+			t.Errorf("zigzag(%v) = %v, want %v", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Package server は地域メッシュコードを変換する HTTP API を提供する。
+//
+// レスポンスはすべて JSON とし、エラーは {"error": "..."} の形式で返す。
+// 不正なメッシュコード・緯度経度・レベルは 400 Bad Request、
+// 日本の国土にかからない緯度経度は 422 Unprocessable Entity とする。
+//
+//	GET  /encode?lat=35.7&lng=139.71&level=3
+//	POST /encode    {"level":"3","points":[{"lat":35.7,"lng":139.71},[139.71,35.7]]}
+//	GET  /decode?code=53394547
+//	GET  /geojson?code=53394547
+//	GET  /children?code=533945
+//	GET  /parent?code=53394547
+//	GET  /neighbors?code=53394547
+//	GET  /tiles/{z}/{x}/{y}.mvt
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+)
+
+// defaultMaxBatchSize POST /encode で一度に変換できる緯度経度の数の既定値
+const defaultMaxBatchSize = 10000
+
+// maxBodyBytes リクエストボディの上限(バイト)
+const maxBodyBytes = 8 << 20
+
+// errInvalidRequest リクエストボディを解析できないことを表す。
+var errInvalidRequest = errors.New("invalid request body")
+
+// Option Handler の設定
+type Option func(*config)
+
+type config struct {
+	maxBatchSize int
+	values       *ValueLayer
+}
+
+// WithMaxBatchSize POST /encode で一度に変換できる緯度経度の数を指定する。既定値は10000。
+func WithMaxBatchSize(n int) Option {
+	return func(c *config) {
+		c.maxBatchSize = n
+	}
+}
+
+// handler 各エンドポイントの処理
+type handler struct {
+	config config
+}
+
+// NewHandler 地域メッシュコードを変換する HTTP API の http.Handler を生成する。
+func NewHandler(opts ...Option) http.Handler {
+	h := &handler{config: config{maxBatchSize: defaultMaxBatchSize}}
+	for _, opt := range opts {
+		opt(&h.config)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/encode", h.encode)
+	mux.HandleFunc("/decode", get(h.decode))
+	mux.HandleFunc("/geojson", get(h.geoJSON))
+	mux.HandleFunc("/children", get(h.children))
+	mux.HandleFunc("/parent", get(h.parent))
+	mux.HandleFunc("/neighbors", get(h.neighbors))
+	mux.HandleFunc("/tiles/", get(h.tile))
+	return mux
+}
+
+// encodeRequest POST /encode のリクエスト
+type encodeRequest struct {
+	Level  japanmesh.Level     `json:"level"`
+	Points []japanmesh.GeoCode `json:"points"`
+}
+
+// encodeResult POST /encode の緯度経度ごとの結果。Code と Error のいずれかを設定する。
+type encodeResult struct {
+	Code  japanmesh.MeshCode `json:"code,omitempty"`
+	Error string             `json:"error,omitempty"`
+}
+
+func (h *handler) encode(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		q := r.URL.Query()
+		level, err := parseLevel(q.Get("level"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		lat, err1 := strconv.ParseFloat(q.Get("lat"), 64)
+		lng, err2 := strconv.ParseFloat(q.Get("lng"), 64)
+		if err1 != nil || err2 != nil {
+			writeError(w, fmt.Errorf("%w: lat=%q lng=%q", japanmesh.ErrInvalidGeoCode, q.Get("lat"), q.Get("lng")))
+			return
+		}
+		code, err := japanmesh.ToCode(japanmesh.GeoCode{Latitude: lat, Longitude: lng}, level)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"code": code, "level": level})
+	case http.MethodPost:
+		var req encodeRequest
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err := dec.Decode(&req); err != nil {
+			if !errors.Is(err, japanmesh.ErrInvalidGeoCode) {
+				err = fmt.Errorf("%w: %v", errInvalidRequest, err)
+			}
+			writeError(w, err)
+			return
+		}
+		level, err := parseLevel(string(req.Level))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if len(req.Points) > h.config.maxBatchSize {
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{
+				"error": fmt.Sprintf("too many points: %d (max %d)", len(req.Points), h.config.maxBatchSize),
+			})
+			return
+		}
+		codes := make([]japanmesh.MeshCode, len(req.Points))
+		results := make([]encodeResult, len(req.Points))
+		var batchErr japanmesh.BatchError
+		if err := japanmesh.ToCodes(req.Points, level, codes); err != nil && !errors.As(err, &batchErr) {
+			writeError(w, err)
+			return
+		}
+		for i, code := range codes {
+			results[i].Code = code
+		}
+		for _, e := range batchErr {
+			results[e.Index].Error = e.Err.Error()
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"level": level, "results": results})
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodHead, http.MethodPost)
+	}
+}
+
+func (h *handler) decode(w http.ResponseWriter, r *http.Request) {
+	code, err := parseCode(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	level, _ := japanmesh.GetLevel(code)
+	bounds, err := japanmesh.ToBounds(code)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	center := bounds.Center()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code":       code,
+		"level":      level,
+		"min_lat":    bounds.Min.Latitude,
+		"min_lng":    bounds.Min.Longitude,
+		"max_lat":    bounds.Max.Latitude,
+		"max_lng":    bounds.Max.Longitude,
+		"center_lat": center.Latitude,
+		"center_lng": center.Longitude,
+	})
+}
+
+func (h *handler) geoJSON(w http.ResponseWriter, r *http.Request) {
+	code, err := parseCode(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	feature, err := japanmesh.ToGeoJSON(code, map[string]interface{}{"code": code})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(feature)
+}
+
+func (h *handler) children(w http.ResponseWriter, r *http.Request) {
+	code, err := parseCode(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	children, err := japanmesh.GetCodes(code)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"code": code, "children": children})
+}
+
+func (h *handler) parent(w http.ResponseWriter, r *http.Request) {
+	code, err := parseCode(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	parent, err := japanmesh.GetParent(code)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: %q has no upper level", err, code))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"code": code, "parent": parent})
+}
+
+func (h *handler) neighbors(w http.ResponseWriter, r *http.Request) {
+	code, err := parseCode(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	neighbors, err := japanmesh.GetNeighbors(code)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"code": code, "neighbors": neighbors})
+}
+
+// get GET と HEAD のみを受け付ける。
+func get(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			methodNotAllowed(w, http.MethodGet, http.MethodHead)
+			return
+		}
+		fn(w, r)
+	}
+}
+
+// parseCode クエリパラメータ code の地域メッシュコードを検証する。
+func parseCode(r *http.Request) (japanmesh.MeshCode, error) {
+	code := japanmesh.MeshCode(r.URL.Query().Get("code"))
+	if err := japanmesh.Validate(code); err != nil {
+		return "", fmt.Errorf("%w: %q", err, code)
+	}
+	return code, nil
+}
+
+// parseLevel レベルを検証する。空の場合は第3次地域区画とする。
+func parseLevel(s string) (japanmesh.Level, error) {
+	if s == "" {
+		return japanmesh.Level3, nil
+	}
+	return japanmesh.ParseLevel(s)
+}
+
+// statusCode エラーに対応する HTTP ステータスコードを返す。
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, japanmesh.ErrInvalidArea):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, japanmesh.ErrInvalidMeshCode),
+		errors.Is(err, japanmesh.ErrInvalidGeoCode),
+		errors.Is(err, japanmesh.ErrInvalidLevel),
+		errors.Is(err, errInvalidRequest):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusCode(err), map[string]string{"error": err.Error()})
+}
+
+func methodNotAllowed(w http.ResponseWriter, methods ...string) {
+	for _, m := range methods {
+		w.Header().Add("Allow", m)
+	}
+	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
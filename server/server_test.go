@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		want       string
+	}{
+		{name: "encode", method: http.MethodGet, target: "/encode?lat=35.70078&lng=139.71475", wantStatus: http.StatusOK, want: `{"code":"53394547","level":"3"}`},
+		{name: "encode half", method: http.MethodGet, target: "/encode?lat=35.70078&lng=139.71475&level=1/2", wantStatus: http.StatusOK, want: `{"code":"533945471","level":"1/2"}`},
+		{name: "encode invalid area", method: http.MethodGet, target: "/encode?lat=10&lng=100", wantStatus: http.StatusUnprocessableEntity, want: `{"error":"invalid area"}`},
+		{name: "encode invalid geocode", method: http.MethodGet, target: "/encode?lat=x&lng=139", wantStatus: http.StatusBadRequest},
+		{name: "encode invalid level", method: http.MethodGet, target: "/encode?lat=35.7&lng=139.7&level=4", wantStatus: http.StatusBadRequest, want: `{"error":"invalid level: \"4\""}`},
+		{
+			name:       "encode batch",
+			method:     http.MethodPost,
+			target:     "/encode",
+			body:       `{"level":"2","points":[{"lat":35.70078,"lng":139.71475},[139.71475,35.70078],"POINT(100 10)"]}`,
+			wantStatus: http.StatusOK,
+			want:       `{"level":"2","results":[{"code":"533945"},{"code":"533945"},{"error":"invalid area"}]}`,
+		},
+		{name: "encode batch invalid geocode", method: http.MethodPost, target: "/encode", body: `{"points":[{"lat":135,"lng":139}]}`, wantStatus: http.StatusBadRequest, want: `{"error":"invalid geocode"}`},
+		{name: "encode batch invalid body", method: http.MethodPost, target: "/encode", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "encode batch too many", method: http.MethodPost, target: "/encode", body: `{"points":[[139,35],[139,35],[139,35],[139,35]]}`, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "encode method", method: http.MethodDelete, target: "/encode", wantStatus: http.StatusMethodNotAllowed},
+		{
+			name:       "decode",
+			method:     http.MethodGet,
+			target:     "/decode?code=533945",
+			wantStatus: http.StatusOK,
+			want:       `{"center_lat":35.70833333333333,"center_lng":139.6875,"code":"533945","level":"2","max_lat":35.75,"max_lng":139.75,"min_lat":35.666666666666664,"min_lng":139.625}`,
+		},
+		{name: "decode invalid meshcode", method: http.MethodGet, target: "/decode?code=53394", wantStatus: http.StatusBadRequest, want: `{"error":"invalid meshcode: \"53394\""}`},
+		{name: "decode missing", method: http.MethodGet, target: "/decode", wantStatus: http.StatusBadRequest},
+		{name: "decode method", method: http.MethodPost, target: "/decode?code=533945", wantStatus: http.StatusMethodNotAllowed},
+		{name: "children", method: http.MethodGet, target: "/children?code=53394547", wantStatus: http.StatusOK, want: `{"children":["533945471","533945472","533945473","533945474"],"code":"53394547"}`},
+		{name: "children one eighth", method: http.MethodGet, target: "/children?code=53394547111", wantStatus: http.StatusBadRequest},
+		{name: "children integrated", method: http.MethodGet, target: "/children?code=533945465", wantStatus: http.StatusBadRequest},
+		{name: "parent", method: http.MethodGet, target: "/parent?code=53394547", wantStatus: http.StatusOK, want: `{"code":"53394547","parent":"533945"}`},
+		{name: "parent level1", method: http.MethodGet, target: "/parent?code=5339", wantStatus: http.StatusBadRequest},
+		{
+			name:       "neighbors",
+			method:     http.MethodGet,
+			target:     "/neighbors?code=533945",
+			wantStatus: http.StatusOK,
+			want:       `{"code":"533945","neighbors":["533955","533956","533946","533936","533935","533934","533944","533954"]}`,
+		},
+		{name: "not found", method: http.MethodGet, target: "/unknown", wantStatus: http.StatusNotFound},
+	}
+	h := NewHandler(WithMaxBatchSize(3))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v, body = %v", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.want != "" && strings.TrimSpace(rec.Body.String()) != tt.want {
+				t.Errorf("body = %v, want %v", rec.Body.String(), tt.want)
+			}
+			if tt.wantStatus >= 400 && tt.wantStatus != http.StatusNotFound {
+				var body map[string]string
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+					t.Errorf("error body = %v", rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_GeoJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/geojson?code=53394547", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/geo+json" {
+		t.Fatalf("status = %v, content type = %v", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	f, err := geojson.UnmarshalFeature(rec.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Properties["code"] != "53394547" || !f.Geometry.IsPolygon() || f.Geometry.Polygon[0][0][0] != 139.725 {
+		t.Errorf("feature = %+v", f)
+	}
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+)
+
+// タイルのレイヤー名
+const (
+	// gridLayer 地域メッシュの境界線(LineString、属性 level)
+	gridLayer = "grid"
+	// valuesLayer 値のある地域メッシュ(Polygon、属性 code、value)
+	valuesLayer = "values"
+)
+
+// タイル
+const (
+	// maxTileZoom 受け付けるズームレベルの上限
+	maxTileZoom = 24
+	// tileBuffer タイルの外側に描く範囲(タイル座標)
+	tileBuffer = 64
+	// maxGridLines 1方向に描く境界線の上限。超える場合は境界線を描かない
+	maxGridLines = 1024
+)
+
+// 境界線を描く範囲(度)。第1次地域区画のコードが取りうる範囲とする
+const (
+	gridMinLat = 20
+	gridMaxLat = 46
+	gridMinLng = 122
+	gridMaxLng = 154
+)
+
+// ValueLayer ベクトルタイルに描く地域メッシュごとの値。NewValueLayer で生成する。
+type ValueLayer struct {
+	// 第1次地域区画ごとの値(メッシュコードの昇順)
+	cells map[japanmesh.MeshCode][]valueCell
+}
+
+type valueCell struct {
+	code   japanmesh.MeshCode
+	bounds japanmesh.Bounds
+	value  float64
+}
+
+// NewValueLayer 地域メッシュごとの値からベクトルタイルのレイヤーを生成する。異なるレベルのメッシュが含まれてもよい。
+// 規格に沿わないメッシュコードが含まれる場合は ErrInvalidMeshCode を返す。
+func NewValueLayer(values map[japanmesh.MeshCode]float64) (*ValueLayer, error) {
+	l := &ValueLayer{cells: map[japanmesh.MeshCode][]valueCell{}}
+	for code, v := range values {
+		if err := japanmesh.Validate(code); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, code)
+		}
+		bounds, err := japanmesh.ToBounds(code)
+		if err != nil {
+			return nil, err
+		}
+		key := code[:4]
+		l.cells[key] = append(l.cells[key], valueCell{code: code, bounds: bounds, value: v})
+	}
+	for _, cells := range l.cells {
+		sort.Slice(cells, func(i, j int) bool { return cells[i].code < cells[j].code })
+	}
+	return l, nil
+}
+
+// WithValueLayer /tiles に地域メッシュごとの値のレイヤーを追加する。
+func WithValueLayer(l *ValueLayer) Option {
+	return func(c *config) {
+		c.values = l
+	}
+}
+
+// tileCoord Web メルカトルのタイル座標
+type tileCoord struct {
+	z, x, y int
+}
+
+// tile GET /tiles/{z}/{x}/{y}.mvt で、地域メッシュの境界線と値をベクトルタイルとして返す。
+// 境界線のレベルはズームレベルから決め、クエリパラメータ level で変更できる。
+func (h *handler) tile(w http.ResponseWriter, r *http.Request) {
+	t, ok := parseTilePath(r.URL.Path)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "tile not found"})
+		return
+	}
+	level := gridLevel(t.z)
+	if s := r.URL.Query().Get("level"); s != "" {
+		var err error
+		if level, err = parseLevel(s); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	grid := newMVTLayer(gridLayer)
+	if err := t.drawGrid(grid, level); err != nil {
+		writeError(w, err)
+		return
+	}
+	values := newMVTLayer(valuesLayer)
+	if h.config.values != nil {
+		t.drawValues(values, h.config.values)
+	}
+	w.Header().Set("Content-Type", "application/vnd.mapbox-vector-tile")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(marshalMVT(grid, values))
+}
+
+// parseTilePath "/tiles/{z}/{x}/{y}.mvt" を解析する。
+func parseTilePath(path string) (tileCoord, bool) {
+	parts := strings.Split(strings.TrimPrefix(path, "/tiles/"), "/")
+	if len(parts) != 3 || !strings.HasSuffix(parts[2], ".mvt") {
+		return tileCoord{}, false
+	}
+	z, err1 := strconv.Atoi(parts[0])
+	x, err2 := strconv.Atoi(parts[1])
+	y, err3 := strconv.Atoi(strings.TrimSuffix(parts[2], ".mvt"))
+	if err1 != nil || err2 != nil || err3 != nil || z < 0 || z > maxTileZoom {
+		return tileCoord{}, false
+	}
+	if n := 1 << z; x < 0 || x >= n || y < 0 || y >= n {
+		return tileCoord{}, false
+	}
+	return tileCoord{z: z, x: x, y: y}, true
+}
+
+// gridLevel ズームレベルに応じた境界線のレベル。メッシュの1辺がおおよそ16画素以上になるレベルとする。
+func gridLevel(z int) japanmesh.Level {
+	switch {
+	case z < 8:
+		return japanmesh.Level1
+	case z < 11:
+		return japanmesh.Level2
+	case z < 12:
+		return japanmesh.Level3
+	case z < 13:
+		return japanmesh.LevelHalf
+	case z < 14:
+		return japanmesh.LevelQuarter
+	}
+	return japanmesh.LevelOneEighth
+}
+
+// bounds タイルの範囲
+func (t tileCoord) bounds() japanmesh.Bounds {
+	n := float64(int(1) << t.z)
+	lat := func(y int) float64 {
+		return math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/n))) * 180 / math.Pi
+	}
+	return japanmesh.Bounds{
+		Min: japanmesh.GeoCode{Latitude: lat(t.y + 1), Longitude: float64(t.x)/n*360 - 180},
+		Max: japanmesh.GeoCode{Latitude: lat(t.y), Longitude: float64(t.x+1)/n*360 - 180},
+	}
+}
+
+// project 緯度経度をタイル座標に変換する。タイルの外側はバッファの範囲に収める。
+func (t tileCoord) project(lat, lng float64) (int, int) {
+	n := float64(int(1) << t.z)
+	rad := lat * math.Pi / 180
+	x := ((lng+180)/360*n - float64(t.x)) * mvtExtent
+	y := ((1-math.Asinh(math.Tan(rad))/math.Pi)/2*n - float64(t.y)) * mvtExtent
+	clamp := func(v float64) int {
+		return int(math.Round(math.Max(-tileBuffer, math.Min(mvtExtent+tileBuffer, v))))
+	}
+	return clamp(x), clamp(y)
+}
+
+// drawGrid タイルにかかる地域メッシュの境界線を描く。
+func (t tileCoord) drawGrid(l *mvtLayer, level japanmesh.Level) error {
+	mesh, err := japanmesh.GetMesh(level)
+	if err != nil {
+		return err
+	}
+	dLat, dLng := mesh.Distance.Lat, mesh.Distance.Lng
+	b := t.bounds()
+	minLat, maxLat := math.Max(b.Min.Latitude, gridMinLat), math.Min(b.Max.Latitude, gridMaxLat)
+	minLng, maxLng := math.Max(b.Min.Longitude, gridMinLng), math.Min(b.Max.Longitude, gridMaxLng)
+	if minLat >= maxLat || minLng >= maxLng {
+		return nil
+	}
+	// 経度は東経100度、緯度は0度を起点とする間隔の倍数。範囲の端の線を丸め誤差で落とさないようにする
+	const epsilon = 1e-9
+	x0, x1 := int(math.Ceil((minLng-100)/dLng-epsilon)), int(math.Floor((maxLng-100)/dLng+epsilon))
+	y0, y1 := int(math.Ceil(minLat/dLat-epsilon)), int(math.Floor(maxLat/dLat+epsilon))
+	if x1-x0 >= maxGridLines || y1-y0 >= maxGridLines {
+		return nil
+	}
+	property := mvtProperty{key: "level", value: string(level)}
+	line := func(lat0, lng0, lat1, lng1 float64) {
+		var g mvtGeometry
+		px0, py0 := t.project(lat0, lng0)
+		px1, py1 := t.project(lat1, lng1)
+		if px0 == px1 && py0 == py1 {
+			return
+		}
+		g.moveTo(px0, py0)
+		g.lineTo([2]int{px1, py1})
+		l.addFeature(mvtLineString, g.commands, property)
+	}
+	for x := x0; x <= x1; x++ {
+		lng := 100 + float64(x)*dLng
+		line(maxLat, lng, minLat, lng)
+	}
+	for y := y0; y <= y1; y++ {
+		lat := float64(y) * dLat
+		line(lat, minLng, lat, maxLng)
+	}
+	return nil
+}
+
+// drawValues タイルにかかる値のある地域メッシュを描く。タイル座標で面積のないメッシュは描かない。
+func (t tileCoord) drawValues(l *mvtLayer, values *ValueLayer) {
+	b := t.bounds()
+	// タイルにかかる第1次地域区画
+	rows := [2]int{int(math.Floor(math.Max(b.Min.Latitude, gridMinLat) * 1.5)), int(math.Floor(math.Min(b.Max.Latitude, gridMaxLat) * 1.5))}
+	cols := [2]int{int(math.Floor(math.Max(b.Min.Longitude, gridMinLng))) - 100, int(math.Floor(math.Min(b.Max.Longitude, gridMaxLng))) - 100}
+	for row := rows[0]; row <= rows[1]; row++ {
+		for col := cols[0]; col <= cols[1]; col++ {
+			for _, c := range values.cells[japanmesh.MeshCode(fmt.Sprintf("%02d%02d", row, col))] {
+				if c.bounds.Max.Latitude <= b.Min.Latitude || c.bounds.Min.Latitude >= b.Max.Latitude ||
+					c.bounds.Max.Longitude <= b.Min.Longitude || c.bounds.Min.Longitude >= b.Max.Longitude {
+					continue
+				}
+				left, top := t.project(c.bounds.Max.Latitude, c.bounds.Min.Longitude)
+				right, bottom := t.project(c.bounds.Min.Latitude, c.bounds.Max.Longitude)
+				if left == right || top == bottom {
+					continue
+				}
+				// 外周は y 軸が下向きの座標で時計回りとする
+				var g mvtGeometry
+				g.moveTo(left, top)
+				g.lineTo([2]int{right, top}, [2]int{right, bottom}, [2]int{left, bottom})
+				g.closePath()
+				l.addFeature(mvtPolygon, g.commands,
+					mvtProperty{key: "code", value: string(c.code)},
+					mvtProperty{key: "value", value: c.value},
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	japanmesh "github.com/keitaro1020/go-japanmesh"
+)
+
+func getTile(t *testing.T, h http.Handler, target string) (int, map[string]decodedLayer) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		return rec.Code, nil
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/vnd.mapbox-vector-tile" {
+		t.Errorf("Content-Type = %v", ct)
+	}
+	return rec.Code, decodeMVT(t, rec.Body.Bytes())
+}
+
+func TestHandler_TileGrid(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		wantLevel string
+		// 南北・東西の境界線の数
+		wantLines int
+	}{
+		// 1辺 0.1758 度のタイルに 0.0125 度間隔の経線と 0.00833 度間隔の緯線
+		{name: "level3", target: "/tiles/11/1818/806.mvt", wantLevel: "3", wantLines: 14 + 17},
+		{name: "level2", target: "/tiles/8/227/100.mvt", wantLevel: "2"},
+		{name: "level override", target: "/tiles/11/1818/806.mvt?level=2", wantLevel: "2", wantLines: 1 + 1},
+		{name: "whole world", target: "/tiles/0/0/0.mvt", wantLevel: "1", wantLines: 33 + 40},
+	}
+	h := NewHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, layers := getTile(t, h, tt.target)
+			if code != http.StatusOK {
+				t.Fatalf("status = %v", code)
+			}
+			grid, ok := layers[gridLayer]
+			if !ok || len(grid.features) == 0 {
+				t.Fatalf("layers = %v", layers)
+			}
+			if tt.wantLines > 0 && len(grid.features) != tt.wantLines {
+				t.Errorf("lines = %v, want %v", len(grid.features), tt.wantLines)
+			}
+			for _, f := range grid.features {
+				if f.geomType != uint64(mvtLineString) || grid.properties(f)["level"] != tt.wantLevel {
+					t.Fatalf("feature = %+v, properties = %v", f, grid.properties(f))
+				}
+				line := decodeGeometry(f.geometry)
+				if len(line) != 1 || len(line[0]) != 2 {
+					t.Fatalf("line = %v", line)
+				}
+				p, q := line[0][0], line[0][1]
+				if p[0] != q[0] && p[1] != q[1] {
+					t.Errorf("line %v is not axis aligned", line)
+				}
+				for _, v := range []int{p[0], p[1], q[0], q[1]} {
+					if v < -tileBuffer || v > mvtExtent+tileBuffer {
+						t.Errorf("line %v is out of buffer", line)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_TileValues(t *testing.T) {
+	values, err := NewValueLayer(map[japanmesh.MeshCode]float64{"53394547": 120, "533945471": 30, "53394548": 80, "64414277": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(WithValueLayer(values))
+	code, layers := getTile(t, h, "/tiles/14/14550/6450.mvt")
+	if code != http.StatusOK {
+		t.Fatalf("status = %v", code)
+	}
+	l := layers[valuesLayer]
+	got := map[string]float64{}
+	for _, f := range l.features {
+		p := l.properties(f)
+		got[p["code"].(string)] = p["value"].(float64)
+		rings := decodeGeometry(f.geometry)
+		if f.geomType != uint64(mvtPolygon) || len(rings) != 1 || len(rings[0]) != 5 {
+			t.Fatalf("feature = %+v, rings = %v", f, rings)
+		}
+		// 外周は y 軸が下向きの座標で時計回り(面積が正)
+		var area int
+		r := rings[0]
+		for i := 0; i+1 < len(r); i++ {
+			area += r[i][0]*r[i+1][1] - r[i+1][0]*r[i][1]
+		}
+		if area <= 0 {
+			t.Errorf("ring %v area = %v, want positive", r, area)
+		}
+	}
+	if len(got) != 2 || got["53394547"] != 120 || got["533945471"] != 30 {
+		t.Errorf("values = %v", got)
+	}
+
+	// 日本の範囲外のタイルには地物がない
+	if code, layers := getTile(t, h, "/tiles/5/0/0.mvt"); code != http.StatusOK || len(layers) != 0 {
+		t.Errorf("status = %v, layers = %v", code, layers)
+	}
+	// 境界線が多すぎる場合は描かない
+	if code, layers := getTile(t, h, "/tiles/4/14/6.mvt?level=1/8"); code != http.StatusOK || len(layers[gridLayer].features) != 0 {
+		t.Errorf("status = %v, grid = %v", code, len(layers[gridLayer].features))
+	}
+}
+
+func TestHandler_TileErrors(t *testing.T) {
+	tests := []struct {
+		target     string
+		wantStatus int
+	}{
+		{target: "/tiles/1/1/1.png", wantStatus: http.StatusNotFound},
+		{target: "/tiles/1/2/0.mvt", wantStatus: http.StatusNotFound},
+		{target: "/tiles/1/0/-1.mvt", wantStatus: http.StatusNotFound},
+		{target: "/tiles/25/0/0.mvt", wantStatus: http.StatusNotFound},
+		{target: "/tiles/1/0/0/0.mvt", wantStatus: http.StatusNotFound},
+		{target: "/tiles/1/0/0.mvt?level=4", wantStatus: http.StatusBadRequest},
+	}
+	h := NewHandler()
+	for _, tt := range tests {
+		if code, _ := getTile(t, h, tt.target); code != tt.wantStatus {
+			t.Errorf("%v status = %v, want %v", tt.target, code, tt.wantStatus)
+		}
+	}
+	if _, err := NewValueLayer(map[japanmesh.MeshCode]float64{"53394": 1}); !errors.Is(err, japanmesh.ErrInvalidMeshCode) {
+		t.Errorf("NewValueLayer() error = %v, want %v", err, japanmesh.ErrInvalidMeshCode)
+	}
+}
@@ -0,0 +1,156 @@
+package japanmesh
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrInvalidKernel = errors.New("invalid kernel")
+
+// gaussianKernelCutoff ガウシアンカーネルで重みを求める範囲(標準偏差の倍数)
+const gaussianKernelCutoff = 3
+
+// Kernel Smooth で周辺のメッシュの値を重み付けする方法。
+// BoxKernel、GaussianKernel、CustomKernel で生成する。
+type Kernel struct {
+	// window 中心のメッシュから重みを求める南北・東西の範囲(メッシュ数)
+	window func(c cell) (int, int)
+	// weight 中心のメッシュから南北に dy、東西に dx 離れたメッシュの重み。
+	// 重みは中心のメッシュの緯度と位置の差のみで決まる。
+	weight func(c cell, dy, dx int) float64
+}
+
+// BoxKernel 中心から南北・東西に radius 個以内のメッシュを同じ重みとするカーネルを生成する。
+func BoxKernel(radius int) (Kernel, error) {
+	if radius < 0 {
+		return Kernel{}, ErrInvalidKernel
+	}
+	return Kernel{
+		window: func(cell) (int, int) { return radius, radius },
+		weight: func(cell, int, int) float64 { return 1 },
+	}, nil
+}
+
+// GaussianKernel メッシュの中心間の測地線距離 d(m)に対して exp(-d²/2σ²) を重みとするカーネルを生成する。
+// 重みは標準偏差 sigma(m)の3倍の距離まで求める。
+func GaussianKernel(sigma float64) (Kernel, error) {
+	if !(sigma > 0) || math.IsInf(sigma, 1) {
+		return Kernel{}, ErrInvalidKernel
+	}
+	return Kernel{
+		window: func(c cell) (int, int) {
+			b := c.bounds()
+			center := b.Center()
+			height := approximateDistance(GeoCode{Latitude: b.Min.Latitude, Longitude: center.Longitude}, GeoCode{Latitude: b.Max.Latitude, Longitude: center.Longitude})
+			// 高緯度側の辺のほうが短いため、北端で幅を求める
+			width := approximateDistance(GeoCode{Latitude: b.Max.Latitude, Longitude: b.Min.Longitude}, b.Max)
+			return int(math.Ceil(gaussianKernelCutoff * sigma / height)), int(math.Ceil(gaussianKernelCutoff * sigma / width))
+		},
+		weight: func(c cell, dy, dx int) float64 {
+			neighbor := cell{level: c.level, y: c.y + dy, x: c.x + dx}
+			d := GeodesicDistance(c.bounds().Center(), neighbor.bounds().Center())
+			if d > gaussianKernelCutoff*sigma {
+				return 0
+			}
+			return math.Exp(-d * d / (2 * sigma * sigma))
+		},
+	}, nil
+}
+
+// CustomKernel 任意の重みのカーネルを生成する。
+// weights は北から南の順の行、各行は西から東の順の重みとし、行数・列数は奇数で中央を中心のメッシュとする。
+// 重みは0以上で、少なくとも1つは正である必要がある。
+func CustomKernel(weights [][]float64) (Kernel, error) {
+	if len(weights)%2 == 0 || len(weights[0])%2 == 0 {
+		return Kernel{}, ErrInvalidKernel
+	}
+	var total float64
+	for _, row := range weights {
+		if len(row) != len(weights[0]) {
+			return Kernel{}, ErrInvalidKernel
+		}
+		for _, w := range row {
+			if !(w >= 0) || math.IsInf(w, 1) {
+				return Kernel{}, ErrInvalidKernel
+			}
+			total += w
+		}
+	}
+	if total == 0 {
+		return Kernel{}, ErrInvalidKernel
+	}
+	ry, rx := len(weights)/2, len(weights[0])/2
+	return Kernel{
+		window: func(cell) (int, int) { return ry, rx },
+		weight: func(_ cell, dy, dx int) float64 { return weights[ry-dy][rx+dx] },
+	}, nil
+}
+
+// Smooth 同じレベルの地域メッシュごとの値を、カーネルで周辺のメッシュと重み付け平均して平滑化する。
+//
+// 値のないメッシュは0として扱い、値のあるメッシュからカーネルの範囲内にあるメッシュの値を返す。
+// 第1次地域区画の境界をまたいで周辺のメッシュをたどり、日本の国土にかからない第1次地域区画のメッシュは除く。
+// values に異なるレベルのメッシュが含まれる場合は ErrInvalidLevel を返す。
+func Smooth(values map[MeshCode]float64, kernel Kernel) (map[MeshCode]float64, error) {
+	if kernel.weight == nil {
+		return nil, ErrInvalidKernel
+	}
+	sources := make(map[cell]float64, len(values))
+	var level Level
+	var ry, rx int
+	for code, v := range values {
+		c, err := toCell(code)
+		if err != nil {
+			return nil, err
+		}
+		if level != "" && c.level != level {
+			return nil, ErrInvalidLevel
+		}
+		level = c.level
+		sources[c] = v
+		wy, wx := kernel.window(c)
+		ry, rx = maxInt(ry, wy), maxInt(rx, wx)
+	}
+
+	targets := make(map[cell]struct{})
+	for c := range sources {
+		for dy := -ry; dy <= ry; dy++ {
+			for dx := -rx; dx <= rx; dx++ {
+				t := cell{level: level, y: c.y + dy, x: c.x + dx}
+				if t.inArea() {
+					targets[t] = struct{}{}
+				}
+			}
+		}
+	}
+
+	// 重みは中心のメッシュの緯度と位置の差のみで決まるため、緯度ごとに使い回す
+	type weightKey struct{ y, dy, dx int }
+	weights := make(map[weightKey]float64)
+	results := make(map[MeshCode]float64, len(targets))
+	for t := range targets {
+		var sum, total float64
+		for dy := -ry; dy <= ry; dy++ {
+			for dx := -rx; dx <= rx; dx++ {
+				n := cell{level: level, y: t.y + dy, x: t.x + dx}
+				if !n.inArea() {
+					continue
+				}
+				key := weightKey{y: t.y, dy: dy, dx: dx}
+				w, ok := weights[key]
+				if !ok {
+					w = kernel.weight(t, dy, dx)
+					weights[key] = w
+				}
+				sum += w * sources[n]
+				total += w
+			}
+		}
+		if total == 0 {
+			continue
+		}
+		code, _ := t.toCode()
+		results[code] = sum / total
+	}
+	return results, nil
+}
@@ -0,0 +1,125 @@
+package japanmesh
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSmooth_Box(t *testing.T) {
+	kernel, err := BoxKernel(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		values map[MeshCode]float64
+		want   map[MeshCode]float64
+	}{
+		{
+			name:   "inside level1",
+			values: map[MeshCode]float64{"53394547": 9},
+			want: map[MeshCode]float64{
+				"53394536": 1, "53394537": 1, "53394538": 1,
+				"53394546": 1, "53394547": 1, "53394548": 1,
+				"53394556": 1, "53394557": 1, "53394558": 1,
+			},
+		},
+		{
+			// 第1次地域区画の南西の角では、西・南・南西の第1次地域区画のメッシュにまたがる
+			name:   "across level1",
+			values: map[MeshCode]float64{"53390000": 9},
+			want: map[MeshCode]float64{
+				"53380709": 1, "53390000": 1, "53390001": 1,
+				"53380719": 1, "53390010": 1, "53390011": 1,
+				"52387799": 1, "52397090": 1, "52397091": 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Smooth(tt.values, kernel)
+			if err != nil {
+				t.Fatalf("Smooth() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Smooth() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmooth_Gaussian(t *testing.T) {
+	kernel, err := GaussianKernel(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Smooth(map[MeshCode]float64{"53394547": 100}, kernel)
+	if err != nil {
+		t.Fatalf("Smooth() error = %v", err)
+	}
+	center := got["53394547"]
+	for _, code := range (MeshCodes{"53394546", "53394548", "53394537", "53394557"}) {
+		if !(got[code] > 0 && got[code] < center) {
+			t.Errorf("Smooth() %v = %v, want between 0 and %v", code, got[code], center)
+		}
+	}
+	// 東西の隣接メッシュは中心から同じ距離にある
+	if math.Abs(got["53394546"]-got["53394548"]) > got["53394546"]*1e-9 {
+		t.Errorf("Smooth() east = %v, west = %v", got["53394548"], got["53394546"])
+	}
+	// 基準地域メッシュの南北の幅(約0.9km)は東西の幅(約1.1km)より狭いため、南の隣接メッシュのほうが中心に近い
+	if !(got["53394537"] > got["53394546"]) {
+		t.Errorf("Smooth() south = %v, west = %v", got["53394537"], got["53394546"])
+	}
+}
+
+func TestSmooth_Custom(t *testing.T) {
+	kernel, err := CustomKernel([][]float64{
+		{0, 2, 0},
+		{0, 1, 0},
+		{0, 0, 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := Smooth(map[MeshCode]float64{"53394547": 3}, kernel)
+	// 南のメッシュは北にある値を重み2で受け取る
+	if got["53394537"] != 2 || got["53394547"] != 1 || got["53394557"] != 0 {
+		t.Errorf("Smooth() got = %v", got)
+	}
+}
+
+func TestSmooth_Error(t *testing.T) {
+	box, _ := BoxKernel(1)
+	tests := []struct {
+		name    string
+		values  map[MeshCode]float64
+		kernel  Kernel
+		wantErr error
+	}{
+		{name: "mixed level", values: map[MeshCode]float64{"53394547": 1, "533945": 1}, kernel: box, wantErr: ErrInvalidLevel},
+		{name: "invalid meshcode", values: map[MeshCode]float64{"53394": 1}, kernel: box, wantErr: ErrInvalidMeshCode},
+		{name: "zero kernel", values: map[MeshCode]float64{"53394547": 1}, kernel: Kernel{}, wantErr: ErrInvalidKernel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Smooth(tt.values, tt.kernel); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Smooth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := BoxKernel(-1); !errors.Is(err, ErrInvalidKernel) {
+		t.Errorf("BoxKernel() error = %v, wantErr %v", err, ErrInvalidKernel)
+	}
+	if _, err := GaussianKernel(0); !errors.Is(err, ErrInvalidKernel) {
+		t.Errorf("GaussianKernel() error = %v, wantErr %v", err, ErrInvalidKernel)
+	}
+	for _, weights := range [][][]float64{{{1, 1}}, {{0}}, {{1, -1, 1}}, {{1, 1, 1}, {1}, {1, 1, 1}}} {
+		if _, err := CustomKernel(weights); !errors.Is(err, ErrInvalidKernel) {
+			t.Errorf("CustomKernel(%v) error = %v, wantErr %v", weights, err, ErrInvalidKernel)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package japanmesh
+
+import "sort"
+
+// TreeAggregator 配下のメッシュの値をまとめる関数。
+// value はそのメッシュ自身の値(ない場合は nil)、children は値を持つ子のメッシュごとの集計値。
+type TreeAggregator[T any] func(value *T, children []T) T
+
+// Tree 地域メッシュコードの階層(第1次地域区画から1/8地域メッシュ)に沿って値を保持する木。
+//
+// 子の数はレベルによって異なり、第2次地域区画は8×8、基準地域メッシュは10×10、
+// 2分の1地域メッシュより下位は4(1〜4の象限)となる。
+// aggregate を指定すると、各メッシュの配下の値の集計値を値の追加・削除のたびに更新する。
+// 統合地域メッシュは階層に含まれないため扱えない。Tree は並行して使用できない。
+type Tree[T any] struct {
+	roots     map[MeshCode]*treeNode[T]
+	aggregate TreeAggregator[T]
+	len       int
+}
+
+type treeNode[T any] struct {
+	code         MeshCode
+	value        T
+	hasValue     bool
+	aggregated   T
+	hasAggregate bool
+	children     []*treeNode[T]
+}
+
+// NewTree 木を生成する。集計値が不要な場合は aggregate に nil を指定する。
+func NewTree[T any](aggregate TreeAggregator[T]) *Tree[T] {
+	return &Tree[T]{roots: make(map[MeshCode]*treeNode[T]), aggregate: aggregate}
+}
+
+// Len 値を持つ地域メッシュコードの数を取得する。
+func (t *Tree[T]) Len() int {
+	return t.len
+}
+
+// Insert 地域メッシュコードに値を設定する。すでに値がある場合は置き換える。
+func (t *Tree[T]) Insert(code MeshCode, value T) error {
+	path, err := t.path(code, true)
+	if err != nil {
+		return err
+	}
+	node := path[len(path)-1]
+	if !node.hasValue {
+		t.len++
+	}
+	node.value, node.hasValue = value, true
+	t.update(path)
+	return nil
+}
+
+// Get 地域メッシュコードの値を取得する。
+func (t *Tree[T]) Get(code MeshCode) (T, bool) {
+	var zero T
+	path, err := t.path(code, false)
+	if err != nil || path == nil || !path[len(path)-1].hasValue {
+		return zero, false
+	}
+	return path[len(path)-1].value, true
+}
+
+// Delete 地域メッシュコードの値を削除する。値があった場合は true を返す。
+func (t *Tree[T]) Delete(code MeshCode) bool {
+	path, err := t.path(code, false)
+	if err != nil || path == nil || !path[len(path)-1].hasValue {
+		return false
+	}
+	node := path[len(path)-1]
+	var zero T
+	node.value, node.hasValue = zero, false
+	t.len--
+	t.update(path)
+	return true
+}
+
+// Aggregate 地域メッシュコード自身とその配下の値の集計値を取得する。
+// aggregate を指定していない場合や、配下に値がない場合は false を返す。
+func (t *Tree[T]) Aggregate(code MeshCode) (T, bool) {
+	var zero T
+	if t.aggregate == nil {
+		return zero, false
+	}
+	path, err := t.path(code, false)
+	if err != nil || path == nil || !path[len(path)-1].hasAggregate {
+		return zero, false
+	}
+	return path[len(path)-1].aggregated, true
+}
+
+// Walk prefix とその配下のメッシュの値を、メッシュコードの昇順(上位のメッシュが先)に fn に渡す。
+// prefix に空文字を指定するとすべての値を対象とする。fn が false を返すと終了する。
+func (t *Tree[T]) Walk(prefix MeshCode, fn func(code MeshCode, value T) bool) error {
+	if prefix == "" {
+		codes := make(MeshCodes, 0, len(t.roots))
+		for code := range t.roots {
+			codes = append(codes, code)
+		}
+		sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+		for _, code := range codes {
+			if !t.roots[code].walk(fn) {
+				break
+			}
+		}
+		return nil
+	}
+	path, err := t.path(prefix, false)
+	if err != nil || path == nil {
+		return err
+	}
+	path[len(path)-1].walk(fn)
+	return nil
+}
+
+// path 第1次地域区画から地域メッシュコードまでのノードを取得する。
+// create が false の場合、ノードがなければ nil を返す。
+func (t *Tree[T]) path(code MeshCode, create bool) ([]*treeNode[T], error) {
+	if err := Validate(code); err != nil {
+		return nil, err
+	}
+	if level, _ := GetLevel(code); !isStandardLevel(level) {
+		return nil, ErrInvalidLevel
+	}
+	codes := SplitCodeByLevel(code)
+	path := make([]*treeNode[T], 0, len(codes))
+	node, ok := t.roots[codes[0]]
+	if !ok {
+		if !create {
+			return nil, nil
+		}
+		node = &treeNode[T]{code: codes[0]}
+		t.roots[codes[0]] = node
+	}
+	path = append(path, node)
+	for _, c := range codes[1:] {
+		i, size := treeChildIndex(c)
+		if node.children == nil {
+			if !create {
+				return nil, nil
+			}
+			node.children = make([]*treeNode[T], size)
+		}
+		child := node.children[i]
+		if child == nil {
+			if !create {
+				return nil, nil
+			}
+			child = &treeNode[T]{code: c}
+			node.children[i] = child
+		}
+		node = child
+		path = append(path, node)
+	}
+	return path, nil
+}
+
+// update 下位のノードから順に集計値を更新し、値を持たなくなったノードを取り除く。
+func (t *Tree[T]) update(path []*treeNode[T]) {
+	for i := len(path) - 1; i >= 0; i-- {
+		node := path[i]
+		var children []T
+		empty := true
+		for _, child := range node.children {
+			if child == nil {
+				continue
+			}
+			empty = false
+			if child.hasAggregate {
+				children = append(children, child.aggregated)
+			}
+		}
+		if empty {
+			node.children = nil
+		}
+		if !node.hasValue && empty {
+			if i == 0 {
+				delete(t.roots, node.code)
+			} else {
+				j, _ := treeChildIndex(node.code)
+				path[i-1].children[j] = nil
+			}
+			continue
+		}
+		if t.aggregate == nil {
+			continue
+		}
+		var value *T
+		if node.hasValue {
+			value = &node.value
+		}
+		node.aggregated = t.aggregate(value, children)
+		node.hasAggregate = node.hasValue || len(children) > 0
+	}
+}
+
+func (n *treeNode[T]) walk(fn func(code MeshCode, value T) bool) bool {
+	if n.hasValue && !fn(n.code, n.value) {
+		return false
+	}
+	for _, child := range n.children {
+		if child != nil && !child.walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// treeChildIndex 上位のメッシュの子の中での位置と子の数を取得する。
+// 位置の順序はメッシュコードの昇順と一致する。
+func treeChildIndex(code MeshCode) (int, int) {
+	switch len(code) {
+	case level2Mesh.Digit:
+		return int(code[4]-'0')*level2Mesh.Division.X + int(code[5]-'0'), level2Mesh.Division.X * level2Mesh.Division.Y
+	case level3Mesh.Digit:
+		return int(code[6]-'0')*level3Mesh.Division.X + int(code[7]-'0'), level3Mesh.Division.X * level3Mesh.Division.Y
+	default:
+		return int(code[len(code)-1] - '1'), 4
+	}
+}
@@ -0,0 +1,118 @@
+package japanmesh
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func sumAggregator(value *float64, children []float64) float64 {
+	var sum float64
+	if value != nil {
+		sum = *value
+	}
+	for _, c := range children {
+		sum += c
+	}
+	return sum
+}
+
+func TestTree(t *testing.T) {
+	tree := NewTree[float64](sumAggregator)
+	values := map[MeshCode]float64{
+		"53394547":    10,
+		"533945471":   1,
+		"53394577":    20,
+		"5339":        100,
+		"53394611":    5,
+		"5440":        3,
+		"53394547124": 0.5,
+	}
+	for code, v := range values {
+		if err := tree.Insert(code, v); err != nil {
+			t.Fatalf("Tree.Insert(%v) error = %v", code, err)
+		}
+	}
+	if got := tree.Len(); got != len(values) {
+		t.Errorf("Tree.Len() got = %v, want %v", got, len(values))
+	}
+
+	if got, ok := tree.Get("53394577"); !ok || got != 20 {
+		t.Errorf("Tree.Get() got = %v, %v, want 20, true", got, ok)
+	}
+	if _, ok := tree.Get("533945"); ok {
+		t.Errorf("Tree.Get() intermediate node got ok = true")
+	}
+
+	aggregates := []struct {
+		code MeshCode
+		want float64
+		ok   bool
+	}{
+		{code: "5339", want: 136.5, ok: true},
+		{code: "533945", want: 31.5, ok: true},
+		{code: "53394547", want: 11.5, ok: true},
+		{code: "5440", want: 3, ok: true},
+		{code: "5238", want: 0, ok: false},
+	}
+	for _, tt := range aggregates {
+		if got, ok := tree.Aggregate(tt.code); ok != tt.ok || got != tt.want {
+			t.Errorf("Tree.Aggregate(%v) got = %v, %v, want %v, %v", tt.code, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	var walked MeshCodes
+	_ = tree.Walk("533945", func(code MeshCode, _ float64) bool {
+		walked = append(walked, code)
+		return true
+	})
+	if want := (MeshCodes{"53394547", "533945471", "53394547124", "53394577"}); !reflect.DeepEqual(walked, want) {
+		t.Errorf("Tree.Walk() got = %v, want %v", walked, want)
+	}
+
+	walked = nil
+	_ = tree.Walk("", func(code MeshCode, _ float64) bool {
+		walked = append(walked, code)
+		return len(walked) < 3
+	})
+	if want := (MeshCodes{"5339", "53394547", "533945471"}); !reflect.DeepEqual(walked, want) {
+		t.Errorf("Tree.Walk() stop got = %v, want %v", walked, want)
+	}
+
+	if !tree.Delete("53394577") || tree.Delete("53394577") {
+		t.Errorf("Tree.Delete() got unexpected result")
+	}
+	if got, _ := tree.Aggregate("533945"); got != 11.5 {
+		t.Errorf("Tree.Aggregate() after delete got = %v, want 11.5", got)
+	}
+	tree.Delete("5440")
+	if _, ok := tree.Aggregate("5440"); ok {
+		t.Errorf("Tree.Aggregate() deleted level1 got ok = true")
+	}
+	if got := tree.Len(); got != len(values)-2 {
+		t.Errorf("Tree.Len() after delete got = %v, want %v", got, len(values)-2)
+	}
+}
+
+func TestTree_Error(t *testing.T) {
+	tree := NewTree[int](nil)
+	tests := []struct {
+		name    string
+		code    MeshCode
+		wantErr error
+	}{
+		{name: "invalid", code: "53394", wantErr: ErrInvalidMeshCode},
+		{name: "integrated", code: "5339452", wantErr: ErrInvalidLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tree.Insert(tt.code, 1); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Tree.Insert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	_ = tree.Insert("5339", 1)
+	if _, ok := tree.Aggregate("5339"); ok {
+		t.Errorf("Tree.Aggregate() without aggregator got ok = true")
+	}
+}